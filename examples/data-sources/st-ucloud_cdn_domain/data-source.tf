@@ -0,0 +1,7 @@
+data "st-ucloud_cdn_domain" "test" {
+  domain = "www.example.com"
+}
+
+output "cname" {
+  value = data.st-ucloud_cdn_domain.test.cname
+}
@@ -0,0 +1,4 @@
+data "st-ucloud_cdn_domains" "test" {
+  name_regex = "\\.example\\.com$"
+  status     = "enable"
+}
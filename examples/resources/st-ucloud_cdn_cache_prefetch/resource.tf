@@ -0,0 +1,10 @@
+resource "st-ucloud_cdn_cache_prefetch" "test" {
+  url_list = [
+    "http://download.example.com/release/app-1.0.0.zip",
+    "http://download.example.com/release/app-1.0.0.zip.sha256",
+  ]
+
+  triggers = {
+    version = "1.0.0"
+  }
+}
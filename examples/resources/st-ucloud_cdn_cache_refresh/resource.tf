@@ -0,0 +1,8 @@
+resource "st-ucloud_cdn_cache_refresh" "test" {
+  type     = "dir"
+  url_list = ["http://test.example.com/static/"]
+
+  triggers = {
+    deploy_hash = "d41d8cd98f00b204e9800998ecf8427e"
+  }
+}
@@ -0,0 +1,7 @@
+# Domain can be imported by domain id or by domain name. Domain in another
+# project can be imported by prefixing the id with `<project_id>/`.
+# Headers in the format of `Name:Value` are imported into `client_header` and
+# `origin_header`.
+terraform import st-ucloud_cdn_domain.test ucdn-xxxxxxxx
+terraform import st-ucloud_cdn_domain.test www.example.com
+terraform import st-ucloud_cdn_domain.test org-xxxxxx/www.example.com
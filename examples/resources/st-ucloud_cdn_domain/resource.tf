@@ -10,6 +10,11 @@ resource "st-ucloud_cdn_domain" "test" {
     origin_port      = 80
     origin_protocol  = "https"
     origin_follow301 = true
+
+    backup_origin {
+      origin_ip_list = ["2.2.2.2"]
+      origin_host    = "backup.example.com"
+    }
   }
 
   cache_conf {
@@ -52,11 +57,17 @@ resource "st-ucloud_cdn_domain" "test" {
       http_code          = 401
       use_regex          = false
     }
+
+    cache_key_rule {
+      path_pattern = "/static/"
+      query_string = "$querystring"
+      ignore       = true
+    }
   }
 
   access_control_conf = {
     enable_refer = false
-    ip_blacklist = ["100.100.100.100", "4.4.4.4"]
+    ip_blacklist = ["100.100.100.100", "4.4.4.0/24"]
     refer_conf = {
       null_refer = true
       refer_list = ["sige-test3.com"]
@@ -65,8 +76,25 @@ resource "st-ucloud_cdn_domain" "test" {
   }
 
   advanced_conf = {
-    http_client_header_list = ["Test:test_client"]
-    http_origin_header_list = ["Test:test_origin"]
-    http_to_https           = false
+    client_header = [
+      { name = "Test", value = "test_client" },
+    ]
+    origin_header = [
+      { name = "Test", value = "test_origin" },
+    ]
+    http_to_https = false
+  }
+}
+
+resource "st-ucloud_cdn_domain" "us3" {
+  domain    = "static.example.com"
+  test_url  = "http://static.example.com/index.html"
+  area_code = "cn"
+  cdn_type  = "web"
+
+  origin_conf {
+    # The bucket domain is sent as an origin address, `origin_host`
+    # defaults to it.
+    origin_ip_list = ["bucket.cn-bj.ufileos.com"]
   }
 }
@@ -0,0 +1,5 @@
+# HTTPS association can be imported by domain id or by domain name. Domain in
+# another project can be imported by prefixing the id with `<project_id>/`.
+terraform import st-ucloud_cdn_domain_ssl_association.test ucdn-xxxxxxxx
+terraform import st-ucloud_cdn_domain_ssl_association.test www.example.com
+terraform import st-ucloud_cdn_domain_ssl_association.test org-xxxxxx/www.example.com
@@ -2,3 +2,11 @@ resource "st-ucloud_cdn_domain_ssl_association" "test" {
   domain_id            = st-ucloud_cdn_domain.test.domain_id
   ssl_certificate_name = st-ucloud_ssl_certificate.test.cert_name
 }
+
+# Use different certificates in China and outside China for a domain whose
+# `area_code` is `all`.
+resource "st-ucloud_cdn_domain_ssl_association" "area" {
+  domain_id        = st-ucloud_cdn_domain.all.domain_id
+  cert_name_cn     = st-ucloud_ssl_certificate.cn.cert_name
+  cert_name_abroad = st-ucloud_ssl_certificate.abroad.cert_name
+}
@@ -0,0 +1,5 @@
+# Certificate can be imported by its name. `cert` and `ca_cert` are read from
+# UCloud, `key` must be supplied in configuration. Certificate in another
+# project can be imported by prefixing the name with `<project_id>/`.
+terraform import st-ucloud_ssl_certificate.test test
+terraform import st-ucloud_ssl_certificate.test org-xxxxxx/test
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+	uerr "github.com/ucloud/ucloud-sdk-go/ucloud/error"
+	"github.com/ucloud/ucloud-sdk-go/ucloud/request"
+)
+
+const (
+	CacheTaskStatusSuccess = "success"
+	CacheTaskStatusFailure = "failure"
+
+	// Maximum count of urls submitted in one refresh or prefetch task.
+	CacheTaskMaxUrlCount = 100
+)
+
+// Submit a refresh task and return its task id.
+// refreshType is `file` for url refreshing and `dir` for directory refreshing.
+func RefreshDomainCache(ctx context.Context, client *ucdn.UCDNClient, projectId, refreshType string, urlList []string) (string, error) {
+	refreshNewUcdnDomainCacheRequest := &ucdn.RefreshNewUcdnDomainCacheRequest{
+		CommonBase: request.CommonBase{
+			ProjectId: &projectId,
+		},
+		Type:    &refreshType,
+		UrlList: urlList,
+	}
+
+	var refreshNewUcdnDomainCacheResponse *ucdn.RefreshNewUcdnDomainCacheResponse
+	refreshDomainCache := func() error {
+		var err error
+		refreshNewUcdnDomainCacheResponse, err = client.RefreshNewUcdnDomainCache(refreshNewUcdnDomainCacheRequest)
+		if err != nil {
+			if cErr, ok := err.(uerr.ClientError); ok && cErr.Retryable() {
+				return err
+			}
+			if Retryable(refreshNewUcdnDomainCacheResponse.RetCode) {
+				return errors.New(refreshNewUcdnDomainCacheResponse.Message)
+			}
+			return backoff.Permanent(err)
+		}
+		return nil
+	}
+	reconnectBackoff := backoff.NewExponentialBackOff()
+	reconnectBackoff.MaxElapsedTime = 30 * time.Second
+	err := backoff.Retry(refreshDomainCache, backoff.WithContext(reconnectBackoff, ctx))
+	if err != nil {
+		return "", err
+	}
+	return refreshNewUcdnDomainCacheResponse.TaskId, nil
+}
+
+// Wait until the refresh task succeeds. An error is returned if the task fails.
+func WaitForRefreshCacheTask(ctx context.Context, client *ucdn.UCDNClient, projectId, taskId string) (*ucdn.TaskInfo, error) {
+	describeNewUcdnRefreshCacheTaskRequest := &ucdn.DescribeNewUcdnRefreshCacheTaskRequest{
+		CommonBase: request.CommonBase{
+			ProjectId: &projectId,
+		},
+		TaskId: []string{taskId},
+	}
+
+	var task *ucdn.TaskInfo
+	describeRefreshCacheTask := func() error {
+		describeNewUcdnRefreshCacheTaskResponse, err := client.DescribeNewUcdnRefreshCacheTask(describeNewUcdnRefreshCacheTaskRequest)
+		if err != nil {
+			if cErr, ok := err.(uerr.ClientError); ok && cErr.Retryable() {
+				return err
+			}
+			if Retryable(describeNewUcdnRefreshCacheTaskResponse.RetCode) {
+				return errors.New(describeNewUcdnRefreshCacheTaskResponse.Message)
+			}
+			return backoff.Permanent(err)
+		}
+		if len(describeNewUcdnRefreshCacheTaskResponse.TaskList) == 0 {
+			return fmt.Errorf("refresh task %s not found", taskId)
+		}
+		task = &describeNewUcdnRefreshCacheTaskResponse.TaskList[0]
+		switch task.Status {
+		case CacheTaskStatusSuccess:
+			return nil
+		case CacheTaskStatusFailure:
+			return backoff.Permanent(fmt.Errorf("refresh task %s failed", taskId))
+		default:
+			return errors.New("unexpected status")
+		}
+	}
+	err := backoff.Retry(describeRefreshCacheTask, newWaitBackOff(ctx))
+	if err != nil {
+		return task, err
+	}
+	return task, nil
+}
+
+// Submit a prefetch task and return its task id.
+func PrefetchDomainCache(ctx context.Context, client *ucdn.UCDNClient, projectId string, urlList []string) (string, error) {
+	prefetchNewUcdnDomainCacheRequest := &ucdn.PrefetchNewUcdnDomainCacheRequest{
+		CommonBase: request.CommonBase{
+			ProjectId: &projectId,
+		},
+		UrlList: urlList,
+	}
+
+	var prefetchNewUcdnDomainCacheResponse *ucdn.PrefetchNewUcdnDomainCacheResponse
+	prefetchDomainCache := func() error {
+		var err error
+		prefetchNewUcdnDomainCacheResponse, err = client.PrefetchNewUcdnDomainCache(prefetchNewUcdnDomainCacheRequest)
+		if err != nil {
+			if cErr, ok := err.(uerr.ClientError); ok && cErr.Retryable() {
+				return err
+			}
+			if Retryable(prefetchNewUcdnDomainCacheResponse.RetCode) {
+				return errors.New(prefetchNewUcdnDomainCacheResponse.Message)
+			}
+			return backoff.Permanent(err)
+		}
+		return nil
+	}
+	reconnectBackoff := backoff.NewExponentialBackOff()
+	reconnectBackoff.MaxElapsedTime = 30 * time.Second
+	err := backoff.Retry(prefetchDomainCache, backoff.WithContext(reconnectBackoff, ctx))
+	if err != nil {
+		return "", err
+	}
+	return prefetchNewUcdnDomainCacheResponse.TaskId, nil
+}
+
+// Wait until the prefetch task succeeds. An error is returned if the task fails,
+// the returned task info contains the status of each url.
+func WaitForPrefetchCacheTask(ctx context.Context, client *ucdn.UCDNClient, projectId, taskId string) (*ucdn.TaskInfo, error) {
+	describeNewUcdnPrefetchCacheTaskRequest := &ucdn.DescribeNewUcdnPrefetchCacheTaskRequest{
+		CommonBase: request.CommonBase{
+			ProjectId: &projectId,
+		},
+		TaskId: []string{taskId},
+	}
+
+	var task *ucdn.TaskInfo
+	describePrefetchCacheTask := func() error {
+		describeNewUcdnPrefetchCacheTaskResponse, err := client.DescribeNewUcdnPrefetchCacheTask(describeNewUcdnPrefetchCacheTaskRequest)
+		if err != nil {
+			if cErr, ok := err.(uerr.ClientError); ok && cErr.Retryable() {
+				return err
+			}
+			if Retryable(describeNewUcdnPrefetchCacheTaskResponse.RetCode) {
+				return errors.New(describeNewUcdnPrefetchCacheTaskResponse.Message)
+			}
+			return backoff.Permanent(err)
+		}
+		if len(describeNewUcdnPrefetchCacheTaskResponse.TaskList) == 0 {
+			return fmt.Errorf("prefetch task %s not found", taskId)
+		}
+		task = &describeNewUcdnPrefetchCacheTaskResponse.TaskList[0]
+		switch task.Status {
+		case CacheTaskStatusSuccess:
+			return nil
+		case CacheTaskStatusFailure:
+			return backoff.Permanent(fmt.Errorf("prefetch task %s failed", taskId))
+		default:
+			return errors.New("unexpected status")
+		}
+	}
+	err := backoff.Retry(describePrefetchCacheTask, newWaitBackOff(ctx))
+	if err != nil {
+		return task, err
+	}
+	return task, nil
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -13,10 +15,29 @@ import (
 
 const (
 	DomainStatusEnable    = "enable"
+	DomainStatusDisable   = "disable"
 	DomainStatusDelete    = "delete"
 	DomainStatusCheckFail = "checkFail"
 )
 
+const (
+	DomainAreaCn     = "cn"
+	DomainAreaAbroad = "abroad"
+	DomainAreaAll    = "all"
+
+	HttpsStatusEnable  = "enable"
+	HttpsStatusDisable = "disable"
+	// HttpsStatusEnabling is reported while HTTPS is being enabled, the
+	// spelling follows the API.
+	HttpsStatusEnabling = "enableing"
+)
+
+// HttpsEnabled reports whether HTTPS is enabled or being enabled with
+// status.
+func HttpsEnabled(status string) bool {
+	return status == HttpsStatusEnable || status == HttpsStatusEnabling
+}
+
 type UpdateCdnHttpsRequest struct {
 	request.CommonBase
 	Region      string
@@ -27,7 +48,16 @@ type UpdateCdnHttpsRequest struct {
 	CertName    string
 }
 
-func WaitForDomainStatus(client *ucdn.UCDNClient, domainId string, targetStatus []string) (string, error) {
+// newWaitBackOff returns the backoff for waiting until a remote operation
+// settles. The waiting time is limited by the deadline of ctx instead of
+// MaxElapsedTime, so that it can be tuned by the timeouts of resources.
+func newWaitBackOff(ctx context.Context) backoff.BackOff {
+	waitBackoff := backoff.NewExponentialBackOff()
+	waitBackoff.MaxElapsedTime = 0
+	return backoff.WithContext(waitBackoff, ctx)
+}
+
+func WaitForDomainStatus(ctx context.Context, client *ucdn.UCDNClient, projectId, domainId string, targetStatus []string) (string, error) {
 	var (
 		getUcdnDomainConfigResponse *ucdn.GetUcdnDomainConfigResponse
 		err                         error
@@ -35,7 +65,7 @@ func WaitForDomainStatus(client *ucdn.UCDNClient, domainId string, targetStatus
 
 	getUcdnDomainConfigRequest := ucdn.GetUcdnDomainConfigRequest{
 		CommonBase: request.CommonBase{
-			ProjectId: &client.GetConfig().ProjectId,
+			ProjectId: &projectId,
 		},
 		DomainId: []string{domainId},
 	}
@@ -60,10 +90,9 @@ func WaitForDomainStatus(client *ucdn.UCDNClient, domainId string, targetStatus
 		}
 		return errors.New("unexpected status")
 	}
-	reconnectBackoff := backoff.NewExponentialBackOff()
-	err = backoff.Retry(getDomainConfig, reconnectBackoff)
+	err = backoff.Retry(getDomainConfig, newWaitBackOff(ctx))
 	if err != nil {
-		return "", errors.New("fail to get expected status")
+		return "", fmt.Errorf("fail to get expected status: %w", err)
 	}
 	if len(getUcdnDomainConfigResponse.DomainList) == 0 {
 		return DomainStatusDelete, nil
@@ -71,41 +100,40 @@ func WaitForDomainStatus(client *ucdn.UCDNClient, domainId string, targetStatus
 	return getUcdnDomainConfigResponse.DomainList[0].Status, nil
 }
 
-func UpdateDomainHttpsConfig(client *ucdn.UCDNClient, domainId string, enable bool, certName string) error {
-	domainConfig, err := GetUcdnDomainConfig(client, domainId)
-	if err != nil {
-		return err
-	}
-	if domainConfig == nil {
-		return errors.New("domain config is nil")
-	}
-	areaCode := domainConfig.AreaCode
-	areas := make([]string, 0)
-	if areaCode == "all" {
-		areas = append(areas, "abroad")
-		areas = append(areas, "cn")
-	} else {
-		areas = append(areas, areaCode)
+// DomainAreas returns the areas that HTTPS can be configured separately for
+// a domain accelerated in areaCode.
+func DomainAreas(areaCode string) []string {
+	if areaCode == DomainAreaAll {
+		return []string{DomainAreaCn, DomainAreaAbroad}
 	}
+	return []string{areaCode}
+}
 
+// UpdateDomainHttpsConfig enables HTTPS with certName or disables HTTPS of
+// domain in area, which is either DomainAreaCn or DomainAreaAbroad.
+func UpdateDomainHttpsConfig(ctx context.Context, client *ucdn.UCDNClient, projectId, domainId, area string, enable bool, certName string) error {
 	reconnectBackoff := backoff.NewExponentialBackOff()
 	reconnectBackoff.MaxElapsedTime = 30 * time.Second
 	updateCdnHttpsRequest := UpdateCdnHttpsRequest{
 		CommonBase: request.CommonBase{
-			ProjectId: &client.GetConfig().ProjectId,
+			ProjectId: &projectId,
 		},
 		Region:   client.GetConfig().Region,
 		Zone:     client.GetConfig().Zone,
+		Areacode: area,
 		DomainId: domainId,
 	}
 	if enable {
-		updateCdnHttpsRequest.HttpsStatus = "enable"
+		updateCdnHttpsRequest.HttpsStatus = HttpsStatusEnable
 		updateCdnHttpsRequest.CertName = certName
 	} else {
-		updateCdnHttpsRequest.HttpsStatus = "disable"
+		updateCdnHttpsRequest.HttpsStatus = HttpsStatusDisable
 	}
 
-	var updateCdnHttpsResponse response.CommonBase
+	var (
+		updateCdnHttpsResponse response.CommonBase
+		err                    error
+	)
 	updateDomainHttpsConfig := func() error {
 		err = client.InvokeAction("UpdateUcdnDomainHttpsConfig", &updateCdnHttpsRequest, &updateCdnHttpsResponse)
 		if err != nil {
@@ -120,26 +148,70 @@ func UpdateDomainHttpsConfig(client *ucdn.UCDNClient, domainId string, enable bo
 		return nil
 	}
 
-	for _, area := range areas {
-		updateCdnHttpsRequest.Areacode = area
-		err = backoff.Retry(updateDomainHttpsConfig, reconnectBackoff)
-		if err != nil {
-			return err
-		}
-		WaitForDomainStatus(client, domainId, []string{DomainStatusEnable})
+	err = backoff.Retry(updateDomainHttpsConfig, backoff.WithContext(reconnectBackoff, ctx))
+	if err != nil {
+		return err
 	}
-
-	return nil
+	_, err = WaitForDomainStatus(ctx, client, projectId, domainId, []string{DomainStatusEnable, DomainStatusDisable})
+	return err
 }
 
-func GetUcdnDomainConfig(client *ucdn.UCDNClient, domainId string) (*DomainConfigInfo, error) {
-	getUcdnDomainConfigRequest := ucdn.GetUcdnDomainConfigRequest{
+func GetUcdnDomainConfig(ctx context.Context, client *ucdn.UCDNClient, projectId, domainId string) (*DomainConfigInfo, error) {
+	return getUcdnDomainConfig(ctx, client, ucdn.GetUcdnDomainConfigRequest{
 		CommonBase: request.CommonBase{
-			ProjectId: &client.GetConfig().ProjectId,
+			ProjectId: &projectId,
 		},
 		DomainId: []string{domainId},
+	})
+}
+
+// GetUcdnDomainConfigByName returns the config of domain with hostname
+// domain, or nil if the domain does not exist.
+func GetUcdnDomainConfigByName(ctx context.Context, client *ucdn.UCDNClient, projectId, domain string) (*DomainConfigInfo, error) {
+	return getUcdnDomainConfig(ctx, client, ucdn.GetUcdnDomainConfigRequest{
+		CommonBase: request.CommonBase{
+			ProjectId: &projectId,
+		},
+		Domain: []string{domain},
+	})
+}
+
+// ListUcdnDomainConfigs returns the config of all domains in project.
+func ListUcdnDomainConfigs(ctx context.Context, client *ucdn.UCDNClient, projectId string) ([]DomainConfigInfo, error) {
+	offset, limit := 0, 50
+	result := make([]DomainConfigInfo, 0)
+	for {
+		domainList, err := describeUcdnDomainConfig(ctx, client, ucdn.GetUcdnDomainConfigRequest{
+			CommonBase: request.CommonBase{
+				ProjectId: &projectId,
+			},
+			Offset: &offset,
+			Limit:  &limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, domainList...)
+		if len(domainList) < limit {
+			break
+		}
+		offset += limit
+	}
+	return result, nil
+}
+
+func getUcdnDomainConfig(ctx context.Context, client *ucdn.UCDNClient, getUcdnDomainConfigRequest ucdn.GetUcdnDomainConfigRequest) (*DomainConfigInfo, error) {
+	domainList, err := describeUcdnDomainConfig(ctx, client, getUcdnDomainConfigRequest)
+	if err != nil {
+		return nil, err
+	}
+	if len(domainList) == 0 {
+		return nil, nil
 	}
+	return &domainList[0], nil
+}
 
+func describeUcdnDomainConfig(ctx context.Context, client *ucdn.UCDNClient, getUcdnDomainConfigRequest ucdn.GetUcdnDomainConfigRequest) ([]DomainConfigInfo, error) {
 	var (
 		getUcdnDomainConfigResponse getUcdnDomainConfigResponse
 		err                         error
@@ -159,15 +231,11 @@ func GetUcdnDomainConfig(client *ucdn.UCDNClient, domainId string) (*DomainConfi
 	}
 	reconnectBackoff := backoff.NewExponentialBackOff()
 	reconnectBackoff.MaxElapsedTime = 30 * time.Second
-	err = backoff.Retry(getDomainConfig, reconnectBackoff)
+	err = backoff.Retry(getDomainConfig, backoff.WithContext(reconnectBackoff, ctx))
 	if err != nil {
 		return nil, err
 	}
-
-	if len(getUcdnDomainConfigResponse.DomainList) == 0 {
-		return nil, nil
-	}
-	return &getUcdnDomainConfigResponse.DomainList[0], nil
+	return getUcdnDomainConfigResponse.DomainList, nil
 }
 
 type CreateDomainConfig struct {
@@ -218,6 +286,7 @@ type CdnCacheConfig struct {
 	CacheHost         *string
 	CacheList         []CdnCacheRule
 	HttpCodeCacheList []CdnCacheRule
+	CacheKeyList      []ucdn.CacheKeyInfo
 }
 
 type DomainConfigInfo struct {
@@ -251,6 +320,10 @@ type UpdateCdnOriginConfig struct {
 	OriginPort      *int64
 	OriginProtocol  *string
 	OriginFollow301 *int64
+
+	BackupOriginEnable *bool
+	BackupOriginIp     []string
+	BackupOriginHost   *string
 }
 
 type UpdateCdnAccessControlConfig struct {
@@ -288,7 +361,7 @@ type UpdateCdnDomainRequest struct {
 	DomainList []UpdateCdnDomainConfig
 }
 
-func UpdateCdnDomain(client *ucdn.UCDNClient, req *UpdateCdnDomainRequest) error {
+func UpdateCdnDomain(ctx context.Context, client *ucdn.UCDNClient, req *UpdateCdnDomainRequest) error {
 	if req == nil || len(req.DomainList) == 0 {
 		return errors.New("UpdateCdnDomainRequest is empty")
 	}
@@ -312,19 +385,27 @@ func UpdateCdnDomain(client *ucdn.UCDNClient, req *UpdateCdnDomainRequest) error
 		}
 		return nil
 	}
-	err = backoff.Retry(updateDomainConfig, reconnectBackoff)
+	err = backoff.Retry(updateDomainConfig, backoff.WithContext(reconnectBackoff, ctx))
 	if err != nil {
 		return err
 	}
 
-	_, err = WaitForDomainStatus(client, req.DomainList[0].DomainId, []string{DomainStatusEnable})
+	_, err = WaitForDomainStatus(ctx, client, req.GetProjectId(), req.DomainList[0].DomainId, []string{DomainStatusEnable, DomainStatusDisable})
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func DeleteDomain(client *ucdn.UCDNClient, domainId string) error {
+func DeleteDomain(ctx context.Context, client *ucdn.UCDNClient, projectId, domainId string) error {
+	_, err := UpdateDomainStatus(ctx, client, projectId, domainId, DomainStatusDelete)
+	return err
+}
+
+// UpdateDomainStatus switches domain to status, which is one of `enable`,
+// `disable` and `delete`, and waits until the switch takes effect. It returns
+// the status that the domain reaches.
+func UpdateDomainStatus(ctx context.Context, client *ucdn.UCDNClient, projectId, domainId, status string) (string, error) {
 	updateUcdnDomainStatusRequest := &struct {
 		request.CommonBase
 		DomainId string
@@ -332,10 +413,10 @@ func DeleteDomain(client *ucdn.UCDNClient, domainId string) error {
 		IsDcdn   bool
 	}{
 		CommonBase: request.CommonBase{
-			ProjectId: &client.GetConfig().ProjectId,
+			ProjectId: &projectId,
 		},
 		DomainId: domainId,
-		Status:   "delete",
+		Status:   status,
 		IsDcdn:   false,
 	}
 
@@ -358,14 +439,10 @@ func DeleteDomain(client *ucdn.UCDNClient, domainId string) error {
 	}
 	reconnectBackoff := backoff.NewExponentialBackOff()
 	reconnectBackoff.MaxElapsedTime = 30 * time.Second
-	err = backoff.Retry(updateDomainStatus, reconnectBackoff)
-	if err != nil {
-		return err
-	}
-	_, err = WaitForDomainStatus(client, domainId, []string{DomainStatusDelete})
+	err = backoff.Retry(updateDomainStatus, backoff.WithContext(reconnectBackoff, ctx))
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return WaitForDomainStatus(ctx, client, projectId, domainId, []string{status})
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -10,10 +11,10 @@ import (
 	"github.com/ucloud/ucloud-sdk-go/ucloud/request"
 )
 
-func AddCertificate(client *ucdn.UCDNClient, name, userCert, privateKey, caCert string) error {
+func AddCertificate(ctx context.Context, client *ucdn.UCDNClient, projectId, name, userCert, privateKey, caCert string) error {
 	addCertificateRequest := &ucdn.AddCertificateRequest{
 		CommonBase: request.CommonBase{
-			ProjectId: &client.GetConfig().ProjectId,
+			ProjectId: &projectId,
 		},
 		CertName:   &name,
 		UserCert:   &userCert,
@@ -36,12 +37,12 @@ func AddCertificate(client *ucdn.UCDNClient, name, userCert, privateKey, caCert
 	}
 	reconnectBackoff := backoff.NewExponentialBackOff()
 	reconnectBackoff.MaxElapsedTime = 30 * time.Second
-	return backoff.Retry(addCertificate, reconnectBackoff)
+	return backoff.Retry(addCertificate, backoff.WithContext(reconnectBackoff, ctx))
 }
 
 // Get ceritificate with specific cert name.
 // If nameList is nil, this function will return all certificates.
-func GetCertificates(client *ucdn.UCDNClient, nameList ...string) ([]*ucdn.CertList, error) {
+func GetCertificates(ctx context.Context, client *ucdn.UCDNClient, projectId string, nameList ...string) ([]*ucdn.CertList, error) {
 	var (
 		result   []*ucdn.CertList
 		indexMap map[string]int
@@ -60,7 +61,7 @@ func GetCertificates(client *ucdn.UCDNClient, nameList ...string) ([]*ucdn.CertL
 	offset, limit := 0, 10
 	getCertificateV2Request := ucdn.GetCertificateV2Request{
 		CommonBase: request.CommonBase{
-			ProjectId: &client.GetConfig().ProjectId,
+			ProjectId: &projectId,
 		},
 		Offset: &offset,
 		Limit:  &limit,
@@ -88,7 +89,7 @@ func GetCertificates(client *ucdn.UCDNClient, nameList ...string) ([]*ucdn.CertL
 	reconnectBackoff := backoff.NewExponentialBackOff()
 	reconnectBackoff.MaxElapsedTime = 30 * time.Second
 	for {
-		err = backoff.Retry(getCertificate, reconnectBackoff)
+		err = backoff.Retry(getCertificate, backoff.WithContext(reconnectBackoff, ctx))
 		if err != nil {
 			return nil, err
 		}
@@ -117,10 +118,10 @@ func GetCertificates(client *ucdn.UCDNClient, nameList ...string) ([]*ucdn.CertL
 	return result, nil
 }
 
-func DeleteCertificate(client *ucdn.UCDNClient, name string) error {
+func DeleteCertificate(ctx context.Context, client *ucdn.UCDNClient, projectId, name string) error {
 	deleteCertificateRequest := ucdn.DeleteCertificateRequest{
 		CommonBase: request.CommonBase{
-			ProjectId: &client.GetConfig().ProjectId,
+			ProjectId: &projectId,
 		},
 		CertName: &name,
 	}
@@ -140,5 +141,5 @@ func DeleteCertificate(client *ucdn.UCDNClient, name string) error {
 	}
 	reconnectBackoff := backoff.NewExponentialBackOff()
 	reconnectBackoff.MaxElapsedTime = 30 * time.Second
-	return backoff.Retry(deleteCertificate, reconnectBackoff)
+	return backoff.Retry(deleteCertificate, backoff.WithContext(reconnectBackoff, ctx))
 }
@@ -0,0 +1,144 @@
+package ucloud
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// parseCertificates decodes all PEM encoded certificates in pemStr in the
+// order they appear.
+func parseCertificates(pemStr string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(pemStr)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return certs, nil
+}
+
+// parseLeafCertificate returns the first certificate in pemStr.
+func parseLeafCertificate(pemStr string) (*x509.Certificate, error) {
+	certs, err := parseCertificates(pemStr)
+	if err != nil {
+		return nil, err
+	}
+	return certs[0], nil
+}
+
+// certificateFingerprint returns the hex encoded SHA-256 digest of the DER
+// encoding of cert.
+func certificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// uniqueCertName generates a certificate name beginning with prefix, which is
+// followed by the creation time and a random suffix.
+func uniqueCertName(prefix string) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return prefix + time.Now().UTC().Format("20060102150405") + hex.EncodeToString(suffix)
+}
+
+// parsePrivateKey decodes a PEM encoded RSA or ECDSA private key in PKCS#1,
+// SEC 1 or PKCS#8 format.
+func parsePrivateKey(pemStr string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(pemStr))
+	if block == nil {
+		return nil, errors.New("no PEM encoded private key found")
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case *ecdsa.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T, only RSA and ECDSA are supported", key)
+	}
+}
+
+// verifyPrivateKey checks that key is the private key of cert.
+func verifyPrivateKey(cert *x509.Certificate, key crypto.Signer) error {
+	pub, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(key.Public()) {
+		return errors.New("private key does not match the public key of certificate")
+	}
+	return nil
+}
+
+// verifyCertificateChain checks that every certificate in chain is issued by
+// the next one, i.e. chain starts with the leaf and is followed by its
+// intermediates in order. On failure it returns the index of the certificate
+// that is expected to be the issuer.
+func verifyCertificateChain(chain []*x509.Certificate) (int, error) {
+	for i := 0; i+1 < len(chain); i++ {
+		if err := chain[i].CheckSignatureFrom(chain[i+1]); err != nil {
+			return i + 1, fmt.Errorf("certificate %q is not issued by %q: %w",
+				chain[i].Subject.String(), chain[i+1].Subject.String(), err)
+		}
+	}
+	return 0, nil
+}
+
+// certificateCoversDomain reports whether any of names, which may contain
+// wildcards like `*.example.com`, matches domain. A wildcard only matches a
+// single label.
+func certificateCoversDomain(names []string, domain string) bool {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+		if name == domain {
+			return true
+		}
+		if strings.HasPrefix(name, "*.") {
+			if i := strings.Index(domain, "."); i > 0 && domain[i+1:] == name[2:] {
+				return true
+			}
+		}
+	}
+	return false
+}
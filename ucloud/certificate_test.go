@@ -0,0 +1,113 @@
+package ucloud
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCertificates(t *testing.T) {
+	leaf, _ := testAccCertificate(t, "leaf.example.com")
+	ca, _ := testAccCertificate(t, "ca.example.com")
+
+	certs, err := parseCertificates(leaf + ca)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 2 || certs[0].Subject.CommonName != "leaf.example.com" || certs[1].Subject.CommonName != "ca.example.com" {
+		t.Fatalf("unexpected certificates: %v", certs)
+	}
+
+	cert, err := parseLeafCertificate(leaf + ca)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fingerprint := certificateFingerprint(cert); len(fingerprint) != 64 || fingerprint != strings.ToLower(fingerprint) {
+		t.Fatalf("unexpected fingerprint: %s", fingerprint)
+	}
+
+	if _, err := parseCertificates("not a certificate"); err == nil {
+		t.Fatal("expected error for invalid certificate")
+	}
+	_, key := testAccCertificate(t, "key.example.com")
+	if _, err := parseCertificates(key); err == nil {
+		t.Fatal("expected error for private key")
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	c := newTestCertificate(t, "test.example.com", nil, nil, time.Now().Add(time.Hour))
+	other := newTestCertificate(t, "other.example.com", nil, nil, time.Now().Add(time.Hour))
+
+	key, err := parsePrivateKey(c.keyPem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyPrivateKey(c.cert, key); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyPrivateKey(other.cert, key); err == nil {
+		t.Fatal("expected error for mismatched private key")
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parsePrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parsePrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parsePrivateKey(c.certPem); err == nil {
+		t.Fatal("expected error for certificate")
+	}
+}
+
+func TestVerifyCertificateChain(t *testing.T) {
+	notAfter := time.Now().Add(time.Hour)
+	root := newTestCertificate(t, "Test Root", nil, nil, notAfter)
+	intermediate := newTestCertificate(t, "Test Intermediate", nil, root, notAfter)
+	leaf := newTestCertificate(t, "test.example.com", nil, intermediate, notAfter)
+
+	if _, err := verifyCertificateChain([]*x509.Certificate{leaf.cert, intermediate.cert, root.cert}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyCertificateChain([]*x509.Certificate{leaf.cert}); err != nil {
+		t.Fatal(err)
+	}
+	if idx, err := verifyCertificateChain([]*x509.Certificate{leaf.cert, root.cert, intermediate.cert}); err == nil || idx != 1 {
+		t.Fatalf("expected misordered chain to fail at 1, got %d, %v", idx, err)
+	}
+}
+
+func TestCertificateCoversDomain(t *testing.T) {
+	cases := []struct {
+		names  []string
+		domain string
+		want   bool
+	}{
+		{[]string{"www.example.com"}, "www.example.com", true},
+		{[]string{"WWW.Example.com"}, "www.example.com.", true},
+		{[]string{"example.com"}, "www.example.com", false},
+		{[]string{"*.example.com"}, "www.example.com", true},
+		{[]string{"*.example.com"}, "example.com", false},
+		{[]string{"*.example.com"}, "a.b.example.com", false},
+		{[]string{"*.b.example.com", "example.com"}, "a.b.example.com", true},
+		{nil, "www.example.com", false},
+	}
+	for _, c := range cases {
+		if got := certificateCoversDomain(c.names, c.domain); got != c.want {
+			t.Errorf("certificateCoversDomain(%v, %q) = %v, want %v", c.names, c.domain, got, c.want)
+		}
+	}
+}
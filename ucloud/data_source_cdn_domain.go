@@ -0,0 +1,401 @@
+package ucloud
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+)
+
+var (
+	_ datasource.DataSource                     = &cdnDomainDataSource{}
+	_ datasource.DataSourceWithConfigure        = &cdnDomainDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &cdnDomainDataSource{}
+)
+
+type cdnDomainDataSourceModel struct {
+	DomainId          types.String `tfsdk:"domain_id"`
+	Domain            types.String `tfsdk:"domain"`
+	Cname             types.String `tfsdk:"cname"`
+	Status            types.String `tfsdk:"status"`
+	CreateTime        types.Int64  `tfsdk:"create_time"`
+	TestUrl           types.String `tfsdk:"test_url"`
+	AreaCode          types.String `tfsdk:"area_code"`
+	CdnType           types.String `tfsdk:"cdn_type"`
+	Tag               types.String `tfsdk:"tag"`
+	HttpsStatusCn     types.String `tfsdk:"https_status_cn"`
+	HttpsStatusAbroad types.String `tfsdk:"https_status_abroad"`
+	CertNameCn        types.String `tfsdk:"cert_name_cn"`
+	CertNameAbroad    types.String `tfsdk:"cert_name_abroad"`
+	ProjectId         types.String `tfsdk:"project_id"`
+
+	OriginConfig *originConfigModel `tfsdk:"origin_conf"`
+
+	CacheConf *cacheConfigModel `tfsdk:"cache_conf"`
+
+	AccessControlConfig types.Object `tfsdk:"access_control_conf"`
+
+	AdvancedConf types.Object `tfsdk:"advanced_conf"`
+}
+
+type cdnDomainDataSource struct {
+	client *ucdn.UCDNClient
+}
+
+func NewCdnDomainDataSource() datasource.DataSource {
+	return &cdnDomainDataSource{}
+}
+
+func (d *cdnDomainDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cdn_domain"
+}
+
+func (d *cdnDomainDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	cacheRuleAttributes := map[string]schema.Attribute{
+		"path_pattern": schema.StringAttribute{
+			Description: "The pattern of path",
+			Computed:    true,
+		},
+		"description": schema.StringAttribute{
+			Description: "The description of rule",
+			Computed:    true,
+		},
+		"ttl": schema.Int64Attribute{
+			Description: "The cache time",
+			Computed:    true,
+		},
+		"cache_unit": schema.StringAttribute{
+			Description: "The unit of caching time.The optional values are `sec`,`min`,`hour` and `day`.",
+			Computed:    true,
+		},
+		"cache_behavior": schema.BoolAttribute{
+			Description: "If caching is enabled.",
+			Computed:    true,
+		},
+		"follow_origin_rule": schema.BoolAttribute{
+			Description: "If follow caching instructions in http header from the origin.",
+			Computed:    true,
+		},
+		"use_regex": schema.BoolAttribute{
+			Description: "If use regex.",
+			Computed:    true,
+		},
+	}
+	httpCodeCacheRuleAttributes := map[string]schema.Attribute{
+		"http_code": schema.Int64Attribute{
+			Description: "Http code.",
+			Computed:    true,
+		},
+	}
+	for name, attribute := range cacheRuleAttributes {
+		httpCodeCacheRuleAttributes[name] = attribute
+	}
+	headerRuleDataSourceAttributes := map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Description: "The name of http header.",
+			Computed:    true,
+		},
+		"value": schema.StringAttribute{
+			Description: "The value of http header.",
+			Computed:    true,
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "This data source provides the configuration of an acceleration domain, including cname, origin, cache, access control, https status, etc.",
+		Attributes: map[string]schema.Attribute{
+			"project_id": projectIdDataSourceAttribute(),
+			"domain_id": schema.StringAttribute{
+				Description: "Id of acceleration domain.Exactly one of `domain_id` and `domain` must be set.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"domain": schema.StringAttribute{
+				Description: "Acceleration domain.Exactly one of `domain_id` and `domain` must be set.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"cname": schema.StringAttribute{
+				Description: "Cname",
+				Computed:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Domain status",
+				Computed:    true,
+			},
+			"create_time": schema.Int64Attribute{
+				Description: "Create time.",
+				Computed:    true,
+			},
+			"test_url": schema.StringAttribute{
+				Description: "Test url",
+				Computed:    true,
+			},
+			"area_code": schema.StringAttribute{
+				Description: "Acceleration area.`cn` represents China.`abroad` represents regions outside China.`all` represents all regions.",
+				Computed:    true,
+			},
+			"cdn_type": schema.StringAttribute{
+				Description: "`web` for website service,`stream` for video service,`download` for download service",
+				Computed:    true,
+			},
+			"tag": schema.StringAttribute{
+				Description: "The group of service.",
+				Computed:    true,
+			},
+			"https_status_cn": schema.StringAttribute{
+				Description: "HTTPS status in China, `enable` or `disable`.",
+				Computed:    true,
+			},
+			"https_status_abroad": schema.StringAttribute{
+				Description: "HTTPS status outside China, `enable` or `disable`.",
+				Computed:    true,
+			},
+			"cert_name_cn": schema.StringAttribute{
+				Description: "Ssl certificate name used in China.",
+				Computed:    true,
+			},
+			"cert_name_abroad": schema.StringAttribute{
+				Description: "Ssl certificate name used outside China.",
+				Computed:    true,
+			},
+			"origin_conf": schema.SingleNestedAttribute{
+				Description: "The configuration of origin",
+				Attributes: map[string]schema.Attribute{
+					"origin_ip_list": schema.ListAttribute{
+						Description: "The ip list of origin",
+						ElementType: types.StringType,
+						Computed:    true,
+					},
+					"origin_host": schema.StringAttribute{
+						Description: "The host of origin",
+						Computed:    true,
+					},
+					"origin_port": schema.Int64Attribute{
+						Description: "The service port of origin",
+						Computed:    true,
+					},
+					"origin_protocol": schema.StringAttribute{
+						Description: "The protocol of origin.",
+						Computed:    true,
+					},
+					"origin_follow301": schema.BoolAttribute{
+						Description: "Whether redirect according to the url from origin.",
+						Computed:    true,
+					},
+					"origin_type": schema.StringAttribute{
+						Description: "The type of origin inferred from `origin_ip_list`, `ip`, `domain` or `us3`.",
+						Computed:    true,
+					},
+					"backup_origin": schema.SingleNestedAttribute{
+						Description: "The configuration of backup origin.It is null if backup origin is disabled.",
+						Attributes: map[string]schema.Attribute{
+							"origin_ip_list": schema.ListAttribute{
+								Description: "The ip list of backup origin",
+								ElementType: types.StringType,
+								Computed:    true,
+							},
+							"origin_host": schema.StringAttribute{
+								Description: "The host of backup origin",
+								Computed:    true,
+							},
+						},
+						Computed: true,
+					},
+				},
+				Computed: true,
+			},
+			"cache_conf": schema.SingleNestedAttribute{
+				Description: "The configuration of cache",
+				Attributes: map[string]schema.Attribute{
+					"cache_rule": schema.ListNestedAttribute{
+						Description: "The list of cache rule",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: cacheRuleAttributes,
+						},
+						Computed: true,
+					},
+					"http_code_cache_rule": schema.ListNestedAttribute{
+						Description: "The list of http code cache rule",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: httpCodeCacheRuleAttributes,
+						},
+						Computed: true,
+					},
+					"cache_key_rule": schema.ListNestedAttribute{
+						Description: "The list of rule on query string of cache key",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"path_pattern": schema.StringAttribute{
+									Description: "The pattern of path.",
+									Computed:    true,
+								},
+								"query_string": schema.StringAttribute{
+									Description: "The query string parameters,each starts with `$` and they are joined with `+`.`$querystring` represents all parameters.",
+									Computed:    true,
+								},
+								"ignore": schema.BoolAttribute{
+									Description: "If the parameters in `query_string` are ignored when caching.",
+									Computed:    true,
+								},
+							},
+						},
+						Computed: true,
+					},
+				},
+				Computed: true,
+			},
+			"access_control_conf": schema.SingleNestedAttribute{
+				Description: "The configuration of access control.",
+				Attributes: map[string]schema.Attribute{
+					"ip_blacklist": schema.ListAttribute{
+						Description: "Request from address in blacklist will be denied.",
+						ElementType: types.StringType,
+						Computed:    true,
+					},
+					"refer_conf": schema.SingleNestedAttribute{
+						Description: "The configuration of anti-leech.",
+						Attributes: map[string]schema.Attribute{
+							"refer_type": schema.StringAttribute{
+								Description: "The type of anti-leech rules, `whitelist` or `blacklist`.",
+								Computed:    true,
+							},
+							"null_refer": schema.BoolAttribute{
+								Description: "When `refer_type` is whitelist and `null_refer` is false,NULL refer requests are not allowed.",
+								Computed:    true,
+							},
+							"refer_list": schema.ListAttribute{
+								Description: "The anti-leech rule list",
+								ElementType: types.StringType,
+								Computed:    true,
+							},
+						},
+						Computed: true,
+					},
+				},
+				Computed: true,
+			},
+			"advanced_conf": schema.SingleNestedAttribute{
+				Description: "The advance configuration.",
+				Attributes: map[string]schema.Attribute{
+					"http_client_header_list": schema.ListAttribute{
+						Description: "Http header added when send response to client.",
+						ElementType: types.StringType,
+						Computed:    true,
+					},
+					"http_origin_header_list": schema.ListAttribute{
+						Description: "Http header added when send request to origin",
+						ElementType: types.StringType,
+						Computed:    true,
+					},
+					"client_header": schema.ListNestedAttribute{
+						Description: "The rules of http header in response to client.Headers not in the format of `Name:Value` are left out.",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: headerRuleDataSourceAttributes,
+						},
+						Computed: true,
+					},
+					"origin_header": schema.ListNestedAttribute{
+						Description: "The rules of http header in request to origin.Headers not in the format of `Name:Value` are left out.",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: headerRuleDataSourceAttributes,
+						},
+						Computed: true,
+					},
+					"http_to_https": schema.BoolAttribute{
+						Description: "If perform a forced conversion from http to https.",
+						Computed:    true,
+					},
+				},
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (d *cdnDomainDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("domain_id"),
+			path.MatchRoot("domain"),
+		),
+	}
+}
+
+func (d *cdnDomainDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	d.client = req.ProviderData.(ucloudClients).cdnClient
+}
+
+func (d *cdnDomainDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model cdnDomainDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var (
+		domainConfig *api.DomainConfigInfo
+		err          error
+	)
+	model.ProjectId = types.StringValue(projectIdOf(d.client, model.ProjectId))
+	if !model.DomainId.IsNull() {
+		domainConfig, err = api.GetUcdnDomainConfig(ctx, d.client, model.ProjectId.ValueString(), model.DomainId.ValueString())
+	} else {
+		domainConfig, err = api.GetUcdnDomainConfigByName(ctx, d.client, model.ProjectId.ValueString(), model.Domain.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to Read CdnDomain", err.Error())
+		return
+	}
+	if domainConfig == nil {
+		resp.Diagnostics.AddError("Domain Not Found", "No acceleration domain matches the given domain_id or domain.")
+		return
+	}
+
+	var domain cdnDomainResourceModel
+	resp.Diagnostics.Append(updateUcloudCdnDomainResourceModel(ctx, &domain, domainConfig)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.DomainId = domain.DomainId
+	model.Domain = domain.Domain
+	model.Tag = domain.Tag
+	model.Cname = domain.Cname
+	model.Status = domain.Status
+	model.CreateTime = domain.CreateTime
+	model.TestUrl = domain.TestUrl
+	model.AreaCode = domain.AreaCode
+	model.CdnType = domain.CdnType
+	model.HttpsStatusCn = types.StringValue(domainConfig.HttpsStatusCn)
+	model.HttpsStatusAbroad = types.StringValue(domainConfig.HttpsStatusAbroad)
+	model.CertNameCn = types.StringValue(domainConfig.CertNameCn)
+	model.CertNameAbroad = types.StringValue(domainConfig.CertNameAbroad)
+	model.OriginConfig = domain.OriginConfig
+	model.CacheConf = domain.CacheConf
+	model.AccessControlConfig = domain.AccessControlConfig
+	// The header rules are read as for an imported domain, which leaves them
+	// null if some headers can't be parsed. Unlike the resource, the data
+	// source still lists the headers that can be parsed then. The others are
+	// already warned about.
+	advancedConf := domain.AdvancedConf.Attributes()
+	if advancedConf["client_header"].IsNull() {
+		advancedConf["client_header"] = types.ListValueMust(types.ObjectType{AttrTypes: headerRuleAttributeTypes},
+			headerRulesOf(domainConfig.AdvancedConf.HttpClientHeader, func(string, error) {}))
+	}
+	if advancedConf["origin_header"].IsNull() {
+		advancedConf["origin_header"] = types.ListValueMust(types.ObjectType{AttrTypes: headerRuleAttributeTypes},
+			headerRulesOf(domainConfig.AdvancedConf.HttpOriginHeader, func(string, error) {}))
+	}
+	model.AdvancedConf = types.ObjectValueMust(advancedConfigAttributeTypes, advancedConf)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
@@ -0,0 +1,114 @@
+package ucloud
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCdnDomainDataSource(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfig("test.example.com", 8080) + `
+data "st-ucloud_cdn_domain" "by_id" {
+  domain_id = st-ucloud_cdn_domain.test.domain_id
+}
+
+data "st-ucloud_cdn_domain" "by_name" {
+  domain = st-ucloud_cdn_domain.test.domain
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.st-ucloud_cdn_domain.by_id", "cname", "st-ucloud_cdn_domain.test", "cname"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domain.by_id", "domain", "test.example.com"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domain.by_id", "origin_conf.origin_port", "8080"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domain.by_id", "origin_conf.origin_ip_list.0", "1.1.1.1"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domain.by_id", "access_control_conf.refer_conf.refer_type", "blacklist"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domain.by_id", "advanced_conf.http_to_https", "true"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domain.by_id", "https_status_cn", "disable"),
+					resource.TestCheckResourceAttrPair("data.st-ucloud_cdn_domain.by_name", "domain_id", "st-ucloud_cdn_domain.test", "domain_id"),
+					resource.TestCheckResourceAttrPair("data.st-ucloud_cdn_domain.by_name", "cache_conf.cache_rule.#", "st-ucloud_cdn_domain.test", "cache_conf.cache_rule.#"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainDataSource_headers(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithAdvanced("test.example.com", `
+    http_client_header_list = ["X-Test:raw", "Not a header"]
+`) + `
+data "st-ucloud_cdn_domain" "test" {
+  domain_id = st-ucloud_cdn_domain.test.domain_id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domain.test", "advanced_conf.http_client_header_list.#", "2"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domain.test", "advanced_conf.client_header.#", "1"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domain.test", "advanced_conf.client_header.0.name", "X-Test"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domain.test", "advanced_conf.client_header.0.value", "raw"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainDataSource_notFound(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+data "st-ucloud_cdn_domain" "test" {
+  domain = "missing.example.com"
+}
+`,
+				ExpectError: regexp.MustCompile("Domain Not Found"),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainDataSource_projectId(t *testing.T) {
+	s := testAccFakeServer(t)
+	domainId := s.CreateDomain("org-other", "other.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+data "st-ucloud_cdn_domain" "test" {
+  domain = "other.example.com"
+}
+`,
+				ExpectError: regexp.MustCompile("Domain Not Found"),
+			},
+			{
+				Config: testAccProviderConfig(s) + `
+data "st-ucloud_cdn_domain" "test" {
+  project_id = "org-other"
+  domain     = "other.example.com"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domain.test", "domain_id", domainId),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domain.test", "project_id", "org-other"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,212 @@
+package ucloud
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+)
+
+var (
+	_ datasource.DataSource              = &cdnDomainsDataSource{}
+	_ datasource.DataSourceWithConfigure = &cdnDomainsDataSource{}
+)
+
+type cdnDomainSummary struct {
+	DomainId          types.String `tfsdk:"domain_id"`
+	Domain            types.String `tfsdk:"domain"`
+	Cname             types.String `tfsdk:"cname"`
+	Status            types.String `tfsdk:"status"`
+	CdnType           types.String `tfsdk:"cdn_type"`
+	AreaCode          types.String `tfsdk:"area_code"`
+	Tag               types.String `tfsdk:"tag"`
+	HttpsStatusCn     types.String `tfsdk:"https_status_cn"`
+	HttpsStatusAbroad types.String `tfsdk:"https_status_abroad"`
+	CertNameCn        types.String `tfsdk:"cert_name_cn"`
+	CertNameAbroad    types.String `tfsdk:"cert_name_abroad"`
+}
+
+type cdnDomainsDataSourceModel struct {
+	NameRegex types.String        `tfsdk:"name_regex"`
+	Tag       types.String        `tfsdk:"tag"`
+	Status    types.String        `tfsdk:"status"`
+	CdnType   types.String        `tfsdk:"cdn_type"`
+	AreaCode  types.String        `tfsdk:"area_code"`
+	Domains   []*cdnDomainSummary `tfsdk:"domains"`
+	ProjectId types.String        `tfsdk:"project_id"`
+}
+
+type cdnDomainsDataSource struct {
+	client *ucdn.UCDNClient
+}
+
+func NewCdnDomainsDataSource() datasource.DataSource {
+	return &cdnDomainsDataSource{}
+}
+
+func (d *cdnDomainsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cdn_domains"
+}
+
+func (d *cdnDomainsDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "This data source provides acceleration domains in ucloud, including domain id, cname, https status, etc.",
+		Attributes: map[string]schema.Attribute{
+			"project_id": projectIdDataSourceAttribute(),
+			"name_regex": schema.StringAttribute{
+				Description: "A regex to filter domains by name.",
+				Optional:    true,
+				Validators: []validator.String{
+					regexValidator{},
+				},
+			},
+			"tag": schema.StringAttribute{
+				Description: "Filter domains by the group of service.",
+				Optional:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Filter domains by status, such as `enable`, `disable` and `check`.",
+				Optional:    true,
+			},
+			"cdn_type": schema.StringAttribute{
+				Description: "Filter domains by cdn type.`web` for website service,`stream` for video service,`download` for download service",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("web", "stream", "download"),
+				},
+			},
+			"area_code": schema.StringAttribute{
+				Description: "Filter domains by acceleration area.`cn` represents China.`abroad` represents regions outside China.`all` represents all regions.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(api.DomainAreaCn, api.DomainAreaAbroad, api.DomainAreaAll),
+				},
+			},
+			"domains": schema.ListNestedAttribute{
+				Description: "List of domains.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"domain_id": schema.StringAttribute{
+							Description: "Id of acceleration domain.",
+							Computed:    true,
+						},
+						"domain": schema.StringAttribute{
+							Description: "Acceleration domain.",
+							Computed:    true,
+						},
+						"cname": schema.StringAttribute{
+							Description: "Cname of acceleration domain.",
+							Computed:    true,
+						},
+						"status": schema.StringAttribute{
+							Description: "Domain status.",
+							Computed:    true,
+						},
+						"cdn_type": schema.StringAttribute{
+							Description: "Cdn type.",
+							Computed:    true,
+						},
+						"area_code": schema.StringAttribute{
+							Description: "Acceleration area.",
+							Computed:    true,
+						},
+						"tag": schema.StringAttribute{
+							Description: "The group of service.",
+							Computed:    true,
+						},
+						"https_status_cn": schema.StringAttribute{
+							Description: "HTTPS status in China, `enable` or `disable`.",
+							Computed:    true,
+						},
+						"https_status_abroad": schema.StringAttribute{
+							Description: "HTTPS status outside China, `enable` or `disable`.",
+							Computed:    true,
+						},
+						"cert_name_cn": schema.StringAttribute{
+							Description: "Ssl certificate name used in China.",
+							Computed:    true,
+						},
+						"cert_name_abroad": schema.StringAttribute{
+							Description: "Ssl certificate name used outside China.",
+							Computed:    true,
+						},
+					},
+				},
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (d *cdnDomainsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	d.client = req.ProviderData.(ucloudClients).cdnClient
+}
+
+func (d *cdnDomainsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model cdnDomainsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !model.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(model.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid Regex", err.Error())
+			return
+		}
+	}
+
+	model.ProjectId = types.StringValue(projectIdOf(d.client, model.ProjectId))
+	domainList, err := api.ListUcdnDomainConfigs(ctx, d.client, model.ProjectId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to List CdnDomains", err.Error())
+		return
+	}
+
+	model.Domains = make([]*cdnDomainSummary, 0, len(domainList))
+	for _, domain := range domainList {
+		if nameRegex != nil && !nameRegex.MatchString(domain.Domain) {
+			continue
+		}
+		if !model.Tag.IsNull() && domain.Tag != model.Tag.ValueString() {
+			continue
+		}
+		if !model.Status.IsNull() && domain.Status != model.Status.ValueString() {
+			continue
+		}
+		if !model.CdnType.IsNull() && domain.CdnType != model.CdnType.ValueString() {
+			continue
+		}
+		if !model.AreaCode.IsNull() && domain.AreaCode != model.AreaCode.ValueString() {
+			continue
+		}
+		model.Domains = append(model.Domains, &cdnDomainSummary{
+			DomainId:          types.StringValue(domain.DomainId),
+			Domain:            types.StringValue(domain.Domain),
+			Cname:             types.StringValue(domain.Cname),
+			Status:            types.StringValue(domain.Status),
+			CdnType:           types.StringValue(domain.CdnType),
+			AreaCode:          types.StringValue(domain.AreaCode),
+			Tag:               types.StringValue(domain.Tag),
+			HttpsStatusCn:     types.StringValue(domain.HttpsStatusCn),
+			HttpsStatusAbroad: types.StringValue(domain.HttpsStatusAbroad),
+			CertNameCn:        types.StringValue(domain.CertNameCn),
+			CertNameAbroad:    types.StringValue(domain.CertNameAbroad),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
@@ -0,0 +1,42 @@
+package ucloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCdnDomainsDataSource(t *testing.T) {
+	s := testAccFakeServer(t)
+	domainId := s.CreateDomain("org-test", "a.example.com")
+	s.CreateDomain("org-test", "b.example.com")
+	s.CreateDomain("org-other", "c.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+data "st-ucloud_cdn_domains" "all" {}
+
+data "st-ucloud_cdn_domains" "test" {
+  name_regex = "^a\\."
+  cdn_type   = "web"
+}
+
+data "st-ucloud_cdn_domains" "none" {
+  tag = "not-exist"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domains.all", "domains.#", "2"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domains.test", "domains.#", "1"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domains.test", "domains.0.domain_id", domainId),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domains.test", "domains.0.domain", "a.example.com"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domains.test", "domains.0.https_status_cn", "disable"),
+					resource.TestCheckResourceAttr("data.st-ucloud_cdn_domains.none", "domains.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -23,6 +23,7 @@ type certificate struct {
 type certDataSourceModel struct {
 	CertNameList types.List     `tfsdk:"cert_name_list"`
 	CertList     []*certificate `tfsdk:"cert_list"`
+	ProjectId    types.String   `tfsdk:"project_id"`
 }
 
 type certDataSource struct {
@@ -41,6 +42,7 @@ func (d *certDataSource) Schema(_ context.Context, req datasource.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Description: "This data source provides certificates configured in ucloud, including certificate name,domains associated with the certificate,etc.",
 		Attributes: map[string]schema.Attribute{
+			"project_id": projectIdDataSourceAttribute(),
 			"cert_name_list": schema.ListAttribute{
 				Description: "List of cert_name.If `cert_name_list` is null,retrieve all certificates.If `cert_name_list` is not null,retrieve certificates with specific name",
 				ElementType: types.StringType,
@@ -83,19 +85,20 @@ func (d *certDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 	}
 
 	state.CertNameList = model.CertNameList
+	state.ProjectId = types.StringValue(projectIdOf(d.client, model.ProjectId))
 
 	var queryList []string
 	resp.Diagnostics.Append(state.CertNameList.ElementsAs(ctx, &queryList, false)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	certs, err := api.GetCertificates(d.client, queryList...)
+	certs, err := api.GetCertificates(ctx, d.client, state.ProjectId.ValueString(), queryList...)
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to get ssl status", err.Error())
 		return
 	}
 
-	state.CertList = make([]*certificate, len(queryList))
+	state.CertList = make([]*certificate, 0, len(certs))
 	for _, cert := range certs {
 		if cert == nil {
 			continue
@@ -0,0 +1,30 @@
+package ucloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccSslCertificateDataSource(t *testing.T) {
+	s := testAccFakeServer(t)
+	cert, key := testAccCertificate(t, "test.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key) + `
+data "st-ucloud_ssl_certificate" "test" {
+  cert_name_list = [st-ucloud_ssl_certificate.test.cert_name]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.st-ucloud_ssl_certificate.test", "cert_list.#", "1"),
+					resource.TestCheckResourceAttr("data.st-ucloud_ssl_certificate.test", "cert_list.0.cert_name", "test-cert"),
+					resource.TestCheckResourceAttr("data.st-ucloud_ssl_certificate.test", "cert_list.0.domains.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package ucloud
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// headerNameRegexp matches a token defined by RFC 7230.
+	headerNameRegexp  = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+	headerValueRegexp = regexp.MustCompile(`^[^\x00-\x08\x0a-\x1f\x7f]*$`)
+)
+
+// headerRule is a rule that adds an http header.
+type headerRule struct {
+	Name  string
+	Value string
+}
+
+// formatHeaderRule serializes rule into the format of `HttpClientHeader` and
+// `HttpOriginHeader` of UCloud API, e.g. `Test:test_client`:
+//
+//	Name:Value
+func formatHeaderRule(rule headerRule) string {
+	return rule.Name + ":" + rule.Value
+}
+
+// parseHeaderRule parses a header rule in the format of UCloud API.
+func parseHeaderRule(s string) (headerRule, error) {
+	name, value, ok := strings.Cut(s, ":")
+	if !ok {
+		return headerRule{}, fmt.Errorf("header %q has no value", s)
+	}
+	rule := headerRule{Name: name, Value: value}
+	if !headerNameRegexp.MatchString(rule.Name) {
+		return rule, fmt.Errorf("header %q has an invalid name", s)
+	}
+	if !headerValueRegexp.MatchString(rule.Value) {
+		return rule, fmt.Errorf("header %q has an invalid value", s)
+	}
+	return rule, nil
+}
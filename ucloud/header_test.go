@@ -0,0 +1,83 @@
+package ucloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestParseHeaderRule(t *testing.T) {
+	cases := []struct {
+		s       string
+		want    headerRule
+		wantErr bool
+	}{
+		{"Test:test_client", headerRule{Name: "Test", Value: "test_client"}, false},
+		{"X-Test:", headerRule{Name: "X-Test", Value: ""}, false},
+		{"Cache-Control:max-age=60, public", headerRule{Name: "Cache-Control", Value: "max-age=60, public"}, false},
+		{"X-Url:http://example.com", headerRule{Name: "X-Url", Value: "http://example.com"}, false},
+		{"X-Test", headerRule{}, true},
+		{"X Test:test", headerRule{}, true},
+		{":test", headerRule{}, true},
+		{"X-Test:a\r\nb", headerRule{}, true},
+	}
+	for _, c := range cases {
+		got, err := parseHeaderRule(c.s)
+		if (err != nil) != c.wantErr {
+			t.Errorf("parseHeaderRule(%q) error = %v, wantErr %v", c.s, err, c.wantErr)
+			continue
+		}
+		if c.wantErr {
+			continue
+		}
+		if got != c.want {
+			t.Errorf("parseHeaderRule(%q) = %+v, want %+v", c.s, got, c.want)
+		}
+		if s := formatHeaderRule(got); s != c.s {
+			t.Errorf("formatHeaderRule(%+v) = %q, want %q", got, s, c.s)
+		}
+	}
+}
+
+func TestHeaderRuleListOf(t *testing.T) {
+	attrPath := path.Root("advanced_conf").AtName("client_header")
+	rules, diags := headerRuleListOf(attrPath, []string{"X-Test:test", "Not a header"})
+	if len(rules.Elements()) != 1 {
+		t.Errorf("expected 1 header rule, got %d", len(rules.Elements()))
+	}
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Fatalf("expected a warning for the invalid header, got %v", diags)
+	}
+	if !diags[0].(diag.DiagnosticWithPath).Path().Equal(attrPath) {
+		t.Errorf("expected warning on %s, got %s", attrPath, diags[0].(diag.DiagnosticWithPath).Path())
+	}
+}
+
+func TestImportHeaderRuleList(t *testing.T) {
+	attrPath := path.Root("advanced_conf").AtName("http_client_header_list")
+
+	rules, diags := importHeaderRuleList(attrPath, []string{"X-Test:test", "Cache-Control:max-age=60"})
+	if len(diags) != 0 {
+		t.Fatalf("expected no diagnostics, got %v", diags)
+	}
+	if len(rules.Elements()) != 2 {
+		t.Errorf("expected 2 header rules, got %d", len(rules.Elements()))
+	}
+
+	rules, diags = importHeaderRuleList(attrPath, []string{"X-Test:test", "Not a header", "Neither a header"})
+	if !rules.IsNull() {
+		t.Errorf("expected null header rules, got %s", rules)
+	}
+	if diags.HasError() || diags.WarningsCount() != 2 {
+		t.Fatalf("expected a warning for each invalid header, got %v", diags)
+	}
+	if !diags[0].(diag.DiagnosticWithPath).Path().Equal(attrPath) {
+		t.Errorf("expected warning on %s, got %s", attrPath, diags[0].(diag.DiagnosticWithPath).Path())
+	}
+
+	rules, diags = importHeaderRuleList(attrPath, nil)
+	if !rules.IsNull() || len(diags) != 0 {
+		t.Errorf("expected null header rules without diagnostics, got %s, %v", rules, diags)
+	}
+}
@@ -0,0 +1,130 @@
+package fakeucdn
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+)
+
+const (
+	taskTypeRefresh  = "refresh"
+	taskTypePrefetch = "prefetch"
+
+	taskStatusProcess = "process"
+)
+
+type task struct {
+	taskType     string
+	projectId    string
+	info         ucdn.TaskInfo
+	pendingPolls int
+}
+
+func (s *Server) createTask(taskType, projectId string, urlList []string) (interface{}, error) {
+	if len(urlList) == 0 {
+		return nil, errorf(RetCodeInvalidParameter, "Missing params [UrlList]")
+	}
+	if len(urlList) > api.CacheTaskMaxUrlCount {
+		return nil, errorf(RetCodeInvalidParameter, "Params [UrlList] not available")
+	}
+
+	now := int(time.Now().Unix())
+	t := &task{
+		taskType:  taskType,
+		projectId: projectId,
+		info: ucdn.TaskInfo{
+			TaskId:     s.nextId(taskType),
+			CreateTime: now,
+			Status:     taskStatusProcess,
+		},
+		pendingPolls: s.PendingPolls,
+	}
+	for _, u := range urlList {
+		t.info.UrlLists = append(t.info.UrlLists, ucdn.UrlProgressInfo{
+			Url:        u,
+			CreateTime: now,
+			Status:     taskStatusProcess,
+		})
+	}
+	s.tasks[t.info.TaskId] = t
+	return struct{ TaskId string }{t.info.TaskId}, nil
+}
+
+func (s *Server) describeTasks(taskType, projectId string, taskIds []string) (interface{}, error) {
+	taskList := make([]ucdn.TaskInfo, 0)
+	for _, taskId := range taskIds {
+		t, ok := s.tasks[taskId]
+		if !ok || t.taskType != taskType || t.projectId != projectId {
+			continue
+		}
+		s.pollTask(t)
+		taskList = append(taskList, t.info)
+	}
+	return struct {
+		TaskList   []ucdn.TaskInfo
+		TotalCount int
+	}{taskList, len(taskList)}, nil
+}
+
+func (s *Server) pollTask(t *task) {
+	if t.info.Status != taskStatusProcess {
+		return
+	}
+	if t.pendingPolls > 0 {
+		t.pendingPolls--
+		return
+	}
+
+	now := int(time.Now().Unix())
+	t.info.Status = api.CacheTaskStatusSuccess
+	for i := range t.info.UrlLists {
+		u := &t.info.UrlLists[i]
+		u.FinishTime = now
+		u.Progress = 100
+		u.Status = api.CacheTaskStatusSuccess
+		if s.failedUrls[u.Url] {
+			u.Status = api.CacheTaskStatusFailure
+			t.info.Status = api.CacheTaskStatusFailure
+		}
+		if s.failedTasks[u.Url] {
+			t.info.Status = api.CacheTaskStatusFailure
+		}
+	}
+}
+
+func (s *Server) refreshNewUcdnDomainCache(projectId string, values url.Values) (interface{}, error) {
+	var req ucdn.RefreshNewUcdnDomainCacheRequest
+	if err := decodeForm(values, &req); err != nil {
+		return nil, err
+	}
+	if req.Type == nil || (*req.Type != "file" && *req.Type != "dir") {
+		return nil, errorf(RetCodeInvalidParameter, "Params [Type] not available")
+	}
+	return s.createTask(taskTypeRefresh, projectId, req.UrlList)
+}
+
+func (s *Server) describeNewUcdnRefreshCacheTask(projectId string, values url.Values) (interface{}, error) {
+	var req ucdn.DescribeNewUcdnRefreshCacheTaskRequest
+	if err := decodeForm(values, &req); err != nil {
+		return nil, err
+	}
+	return s.describeTasks(taskTypeRefresh, projectId, req.TaskId)
+}
+
+func (s *Server) prefetchNewUcdnDomainCache(projectId string, values url.Values) (interface{}, error) {
+	var req ucdn.PrefetchNewUcdnDomainCacheRequest
+	if err := decodeForm(values, &req); err != nil {
+		return nil, err
+	}
+	return s.createTask(taskTypePrefetch, projectId, req.UrlList)
+}
+
+func (s *Server) describeNewUcdnPrefetchCacheTask(projectId string, values url.Values) (interface{}, error) {
+	var req ucdn.DescribeNewUcdnPrefetchCacheTaskRequest
+	if err := decodeForm(values, &req); err != nil {
+		return nil, err
+	}
+	return s.describeTasks(taskTypePrefetch, projectId, req.TaskId)
+}
@@ -0,0 +1,145 @@
+package fakeucdn
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+)
+
+// certificateKey identifies a certificate, as certificate names are only
+// unique within a project.
+type certificateKey struct {
+	projectId string
+	name      string
+}
+
+type certificate struct {
+	name       string
+	projectId  string
+	userCert   string
+	privateKey string
+	caCert     string
+}
+
+func (s *Server) certList(c *certificate) ucdn.CertList {
+	certList := ucdn.CertList{
+		CertName: c.name,
+		UserCert: c.userCert,
+		CaCert:   c.caCert,
+		Domains:  make([]string, 0),
+	}
+	if block, _ := pem.Decode([]byte(c.userCert)); block != nil {
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certList.CommonName = cert.Subject.CommonName
+			certList.DnsName = strings.Join(cert.DNSNames, ",")
+			certList.BeginTime = int(cert.NotBefore.Unix())
+			certList.EndTime = int(cert.NotAfter.Unix())
+		}
+	}
+	for _, d := range s.domains {
+		if d.projectId != c.projectId {
+			continue
+		}
+		if (api.HttpsEnabled(d.config.HttpsStatusCn) && d.config.CertNameCn == c.name) ||
+			(api.HttpsEnabled(d.config.HttpsStatusAbroad) && d.config.CertNameAbroad == c.name) {
+			certList.Domains = append(certList.Domains, d.config.Domain)
+		}
+	}
+	sort.Strings(certList.Domains)
+	certList.DomainCount = len(certList.Domains)
+	return certList
+}
+
+func (s *Server) addCertificate(projectId string, values url.Values) (interface{}, error) {
+	var req ucdn.AddCertificateRequest
+	if err := decodeForm(values, &req); err != nil {
+		return nil, err
+	}
+	if req.CertName == nil || req.UserCert == nil || req.PrivateKey == nil {
+		return nil, errorf(RetCodeInvalidParameter, "Missing params [CertName, UserCert, PrivateKey]")
+	}
+	if _, ok := s.certificates[certificateKey{projectId, *req.CertName}]; ok {
+		return nil, errorf(RetCodeAlreadyExists, "certificate %s already exists", *req.CertName)
+	}
+	if block, _ := pem.Decode([]byte(*req.UserCert)); block == nil {
+		return nil, errorf(RetCodeInvalidParameter, "Params [UserCert] not available")
+	}
+
+	c := &certificate{
+		name:       *req.CertName,
+		projectId:  projectId,
+		userCert:   *req.UserCert,
+		privateKey: *req.PrivateKey,
+	}
+	if req.CaCert != nil {
+		c.caCert = *req.CaCert
+	}
+	s.certificates[certificateKey{projectId, c.name}] = c
+	return nil, nil
+}
+
+func (s *Server) getCertificateV2(projectId string, values url.Values) (interface{}, error) {
+	var req ucdn.GetCertificateV2Request
+	if err := decodeForm(values, &req); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(s.certificates))
+	for key := range s.certificates {
+		if key.projectId == projectId {
+			names = append(names, key.name)
+		}
+	}
+	sort.Strings(names)
+
+	offset, limit := 0, len(names)
+	if req.Offset != nil {
+		offset = *req.Offset
+	}
+	if req.Limit != nil && *req.Limit > 0 {
+		limit = *req.Limit
+	}
+	if offset > len(names) {
+		offset = len(names)
+	}
+	if offset+limit > len(names) {
+		limit = len(names) - offset
+	}
+
+	certList := make([]ucdn.CertList, 0, limit)
+	for _, name := range names[offset : offset+limit] {
+		c := s.certList(s.certificates[certificateKey{projectId, name}])
+		if s.HideCertificateContent {
+			c.UserCert, c.CaCert = "", ""
+		}
+		certList = append(certList, c)
+	}
+	return struct {
+		CertList   []ucdn.CertList
+		TotalCount int
+	}{certList, len(names)}, nil
+}
+
+func (s *Server) deleteCertificate(projectId string, values url.Values) (interface{}, error) {
+	var req ucdn.DeleteCertificateRequest
+	if err := decodeForm(values, &req); err != nil {
+		return nil, err
+	}
+	if req.CertName == nil {
+		return nil, errorf(RetCodeInvalidParameter, "Missing params [CertName]")
+	}
+	c, ok := s.certificates[certificateKey{projectId, *req.CertName}]
+	if !ok {
+		return nil, errorf(RetCodeNotFound, "certificate %s not found", *req.CertName)
+	}
+	if certList := s.certList(c); certList.DomainCount > 0 {
+		return nil, errorf(RetCodeInvalidParameter, "certificate %s is in use", c.name)
+	}
+	delete(s.certificates, certificateKey{projectId, c.name})
+	return nil, nil
+}
@@ -0,0 +1,367 @@
+package fakeucdn
+
+import (
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+)
+
+const (
+	domainStatusCheck    = "check"
+	domainStatusUpdating = "updating"
+	domainStatusDeleting = "deleting"
+)
+
+type domain struct {
+	projectId string
+	config    api.DomainConfigInfo
+
+	// The status that the domain moves to after pendingPolls reads.
+	targetStatus string
+	pendingPolls int
+}
+
+func (d *domain) transit(status, targetStatus string, pendingPolls int) {
+	d.config.Status = status
+	d.targetStatus = targetStatus
+	d.pendingPolls = pendingPolls
+}
+
+// settledStatus returns the status that the domain returns to after an
+// update of its config, which keeps a disabled domain disabled.
+func (d *domain) settledStatus() string {
+	status := d.config.Status
+	if d.targetStatus != "" {
+		status = d.targetStatus
+	}
+	if status == api.DomainStatusDisable {
+		return api.DomainStatusDisable
+	}
+	return api.DomainStatusEnable
+}
+
+// poll moves the domain towards its target status, it returns false if
+// the domain has been deleted.
+func (s *Server) poll(d *domain) bool {
+	if d.targetStatus == "" {
+		return true
+	}
+	if d.pendingPolls > 0 {
+		d.pendingPolls--
+		return true
+	}
+	if d.targetStatus == api.DomainStatusDelete {
+		delete(s.domains, d.config.DomainId)
+		return false
+	}
+	d.config.Status = d.targetStatus
+	d.targetStatus = ""
+	return true
+}
+
+func (s *Server) findDomain(projectId, domainId string) (*domain, error) {
+	d, ok := s.domains[domainId]
+	if !ok || d.projectId != projectId {
+		return nil, errorf(RetCodeNotFound, "domain %s not found", domainId)
+	}
+	return d, nil
+}
+
+func (s *Server) batchCreateNewUcdnDomain(projectId string, values url.Values) (interface{}, error) {
+	var req api.CreateCdnDomainRequest
+	if err := decodeForm(values, &req); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		Domain   string
+		DomainId string
+		RetCode  int
+		Message  string
+	}
+	results := make([]result, 0, len(req.DomainList))
+	for _, conf := range req.DomainList {
+		if s.domainExists(conf.Domain) {
+			results = append(results, result{
+				Domain:  conf.Domain,
+				RetCode: RetCodeAlreadyExists,
+				Message: "domain already exists",
+			})
+			continue
+		}
+
+		d := &domain{
+			projectId: projectId,
+			config: api.DomainConfigInfo{
+				DomainId:          s.nextId("ucdn"),
+				Domain:            conf.Domain,
+				TestUrl:           conf.TestUrl,
+				AreaCode:          "cn",
+				CdnType:           "web",
+				Tag:               "Default",
+				CreateTime:        int(time.Now().Unix()),
+				HttpsStatusCn:     "disable",
+				HttpsStatusAbroad: "disable",
+			},
+		}
+		d.config.Cname = d.config.DomainId + ".ucloud.fake"
+		if conf.AreaCode != nil {
+			d.config.AreaCode = *conf.AreaCode
+		}
+		if conf.CdnType != nil {
+			d.config.CdnType = *conf.CdnType
+		}
+		if conf.Tag != nil {
+			d.config.Tag = *conf.Tag
+		}
+		d.config.OriginConf.OriginIpList = conf.OriginIp
+		d.config.OriginConf.OriginHost = conf.OriginHost
+		d.config.OriginConf.OriginPort = 80
+		d.config.OriginConf.OriginProtocol = "http"
+		for _, c := range conf.CacheConf {
+			d.config.CacheConf.CacheList = append(d.config.CacheConf.CacheList, api.CdnCacheRule{
+				PathPattern:   c.PathPattern,
+				CacheTTL:      int(c.CacheTTL),
+				CacheUnit:     c.CacheUnit,
+				CacheBehavior: c.CacheBehavior,
+			})
+		}
+
+		targetStatus := api.DomainStatusEnable
+		if s.auditFail[conf.Domain] {
+			targetStatus = api.DomainStatusCheckFail
+		}
+		d.transit(domainStatusCheck, targetStatus, s.PendingPolls)
+		s.domains[d.config.DomainId] = d
+
+		results = append(results, result{
+			Domain:   conf.Domain,
+			DomainId: d.config.DomainId,
+		})
+	}
+
+	return struct{ DomainList []result }{results}, nil
+}
+
+func (s *Server) domainExists(name string) bool {
+	for _, d := range s.domains {
+		if d.config.Domain == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getUcdnDomainConfig(projectId string, values url.Values) (interface{}, error) {
+	var req ucdn.GetUcdnDomainConfigRequest
+	if err := decodeForm(values, &req); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(s.domains))
+	for id := range s.domains {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	domainList := make([]api.DomainConfigInfo, 0)
+	for _, id := range ids {
+		d := s.domains[id]
+		if d.projectId != projectId {
+			continue
+		}
+		if len(req.DomainId) > 0 && !contains(req.DomainId, d.config.DomainId) {
+			continue
+		}
+		if len(req.Domain) > 0 && !contains(req.Domain, d.config.Domain) {
+			continue
+		}
+		if !s.poll(d) {
+			continue
+		}
+		domainList = append(domainList, d.config)
+	}
+
+	offset, limit := 0, len(domainList)
+	if req.Offset != nil {
+		offset = *req.Offset
+	}
+	if req.Limit != nil && *req.Limit > 0 {
+		limit = *req.Limit
+	}
+	if offset > len(domainList) {
+		offset = len(domainList)
+	}
+	if offset+limit > len(domainList) {
+		limit = len(domainList) - offset
+	}
+
+	return struct {
+		DomainList []api.DomainConfigInfo
+		TotalCount int
+	}{domainList[offset : offset+limit], len(domainList)}, nil
+}
+
+func (s *Server) updateUcdnDomainConfig(projectId string, values url.Values) (interface{}, error) {
+	var req api.UpdateCdnDomainRequest
+	if err := decodeForm(values, &req); err != nil {
+		return nil, err
+	}
+
+	for _, conf := range req.DomainList {
+		d, err := s.findDomain(projectId, conf.DomainId)
+		if err != nil {
+			return nil, err
+		}
+		applyOriginConfig(&d.config.OriginConf, &conf.OriginConf)
+		applyAccessControlConfig(&d.config.AccessControlConf, &conf.AccessControlConf)
+		applyCacheConfig(&d.config.CacheConf, &conf.CacheConf)
+		applyAdvancedConfig(&d.config.AdvancedConf, &conf.AdvancedConf)
+		d.transit(domainStatusUpdating, d.settledStatus(), s.PendingPolls)
+	}
+	return nil, nil
+}
+
+func applyOriginConfig(dst *ucdn.OriginConf, src *api.UpdateCdnOriginConfig) {
+	if len(src.OriginIp) > 0 {
+		dst.OriginIpList = src.OriginIp
+	}
+	if src.OriginHost != nil {
+		dst.OriginHost = *src.OriginHost
+	}
+	if src.OriginPort != nil {
+		dst.OriginPort = int(*src.OriginPort)
+	}
+	if src.OriginProtocol != nil {
+		dst.OriginProtocol = *src.OriginProtocol
+	}
+	if src.OriginFollow301 != nil {
+		dst.OriginFollow301 = int(*src.OriginFollow301)
+	}
+	if src.BackupOriginEnable != nil {
+		dst.BackupOriginEnable = *src.BackupOriginEnable
+		dst.BackupOriginIpList = nil
+		dst.BackupOriginHost = ""
+		if dst.BackupOriginEnable {
+			dst.BackupOriginIpList = src.BackupOriginIp
+			if src.BackupOriginHost != nil {
+				dst.BackupOriginHost = *src.BackupOriginHost
+			}
+		}
+	}
+}
+
+func applyAccessControlConfig(dst *ucdn.AccessControlConf, src *api.UpdateCdnAccessControlConfig) {
+	if src.IpBlackListEmpty {
+		dst.IpBlackList = nil
+	} else if len(src.IpBlackList) > 0 {
+		dst.IpBlackList = src.IpBlackList
+	}
+	if src.ReferConf.ReferType != nil {
+		dst.ReferConf.ReferType = *src.ReferConf.ReferType
+	}
+	if src.ReferConf.NullRefer != nil {
+		dst.ReferConf.NullRefer = *src.ReferConf.NullRefer
+	}
+	if src.EnableRefer {
+		dst.ReferConf.ReferList = src.ReferConf.ReferList
+	} else {
+		dst.ReferConf.ReferList = nil
+	}
+}
+
+func applyCacheConfig(dst *api.CdnCacheConfig, src *api.CdnCacheConfig) {
+	if src.CacheHost != nil {
+		dst.CacheHost = src.CacheHost
+	}
+	// Cache rules are replaced as a whole.
+	if len(src.CacheList) > 0 || len(src.CacheKeyList) > 0 {
+		dst.CacheList = src.CacheList
+		dst.HttpCodeCacheList = src.HttpCodeCacheList
+		dst.CacheKeyList = src.CacheKeyList
+	}
+}
+
+func applyAdvancedConfig(dst *ucdn.AdvancedConf, src *api.UpdateCdnAdvancedConfig) {
+	if src.HttpClientHeaderEmpty {
+		dst.HttpClientHeader = nil
+	} else if len(src.HttpClientHeader) > 0 {
+		dst.HttpClientHeader = src.HttpClientHeader
+	}
+	if src.HttpOriginHeaderEmpty {
+		dst.HttpOriginHeader = nil
+	} else if len(src.HttpOriginHeader) > 0 {
+		dst.HttpOriginHeader = src.HttpOriginHeader
+	}
+	if src.Http2Https != nil {
+		dst.Http2Https = *src.Http2Https
+	}
+}
+
+func (s *Server) updateUcdnDomainStatus(projectId string, values url.Values) (interface{}, error) {
+	var req struct {
+		DomainId string
+		Status   string
+	}
+	if err := decodeForm(values, &req); err != nil {
+		return nil, err
+	}
+
+	d, err := s.findDomain(projectId, req.DomainId)
+	if err != nil {
+		return nil, err
+	}
+	switch req.Status {
+	case api.DomainStatusDelete:
+		d.transit(domainStatusDeleting, api.DomainStatusDelete, s.PendingPolls)
+	case api.DomainStatusEnable, api.DomainStatusDisable:
+		d.transit(domainStatusUpdating, req.Status, s.PendingPolls)
+	default:
+		return nil, errorf(RetCodeInvalidParameter, "Params [Status] not available")
+	}
+	return nil, nil
+}
+
+func (s *Server) updateUcdnDomainHttpsConfig(projectId string, values url.Values) (interface{}, error) {
+	var req api.UpdateCdnHttpsRequest
+	if err := decodeForm(values, &req); err != nil {
+		return nil, err
+	}
+
+	d, err := s.findDomain(projectId, req.DomainId)
+	if err != nil {
+		return nil, err
+	}
+	if req.HttpsStatus == "enable" {
+		if _, ok := s.certificates[certificateKey{projectId, req.CertName}]; !ok {
+			return nil, errorf(RetCodeNotFound, "certificate %s not found", req.CertName)
+		}
+	} else {
+		req.CertName = ""
+	}
+
+	switch req.Areacode {
+	case "cn":
+		d.config.HttpsStatusCn = req.HttpsStatus
+		d.config.CertNameCn = req.CertName
+	case "abroad":
+		d.config.HttpsStatusAbroad = req.HttpsStatus
+		d.config.CertNameAbroad = req.CertName
+	default:
+		return nil, errorf(RetCodeInvalidParameter, "Params [Areacode] not available")
+	}
+	d.transit(domainStatusUpdating, d.settledStatus(), s.PendingPolls)
+	return nil, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
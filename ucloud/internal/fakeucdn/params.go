@@ -0,0 +1,146 @@
+package fakeucdn
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// commonParams are accepted by every action.
+var commonParams = map[string]bool{
+	"Action":        true,
+	"PublicKey":     true,
+	"Signature":     true,
+	"SecurityToken": true,
+	"ProjectId":     true,
+	"Region":        true,
+	"Zone":          true,
+}
+
+// The actions below have no request struct in ucloud-sdk-go, their params
+// follow the UCloud API documentation.
+
+type batchCreateNewUcdnDomainParams struct {
+	DomainList []struct {
+		Domain     string
+		OriginIp   []string
+		OriginHost string
+		TestUrl    string
+		CacheConf  []struct {
+			PathPattern   string
+			CacheTTL      int64
+			CacheUnit     string
+			CacheBehavior bool
+		}
+		AreaCode string
+		CdnType  string
+		Tag      string
+	}
+}
+
+type cacheRuleParams struct {
+	CacheBehavior    bool
+	CacheTTL         int
+	CacheUnit        string
+	Description      string
+	FollowOriginRule bool
+	HttpCodePattern  string
+	PathPattern      string
+	UseRegex         bool
+}
+
+type updateUcdnDomainConfigParams struct {
+	DomainList []struct {
+		DomainId   string
+		OriginConf struct {
+			OriginIp           []string
+			OriginHost         string
+			OriginPort         int64
+			OriginProtocol     string
+			OriginFollow301    int64
+			BackupOriginEnable bool
+			BackupOriginIp     []string
+			BackupOriginHost   string
+		}
+		AccessControlConf struct {
+			IpBlackList      []string
+			IpBlackListEmpty bool
+			ReferConf        struct {
+				ReferType int
+				NullRefer int
+				ReferList []string
+			}
+			EnableRefer bool
+		}
+		CacheConf struct {
+			CacheHost         string
+			CacheList         []cacheRuleParams
+			HttpCodeCacheList []cacheRuleParams
+			CacheKeyList      []struct {
+				Ignore      bool
+				PathPattern string
+				QueryString string
+			}
+		}
+		AdvancedConf struct {
+			HttpClientHeader      []string
+			HttpClientHeaderEmpty bool
+			HttpOriginHeader      []string
+			HttpOriginHeaderEmpty bool
+			Http2Https            bool
+		}
+	}
+}
+
+type updateUcdnDomainHttpsConfigParams struct {
+	Areacode    string
+	DomainId    string
+	HttpsStatus string
+	CertName    string
+}
+
+// checkParams rejects the form values that are neither common params nor
+// fields of params.
+func checkParams(values url.Values, params interface{}) error {
+	t := reflect.TypeOf(params)
+	for key := range values {
+		if commonParams[key] {
+			continue
+		}
+		if !hasParam(t, strings.Split(key, ".")) {
+			return errorf(RetCodeInvalidParameter, "Params [%s] not supported", key)
+		}
+	}
+	return nil
+}
+
+func hasParam(t reflect.Type, path []string) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if len(path) == 0 {
+			return false
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Anonymous || !field.IsExported() || field.Name != path[0] {
+				continue
+			}
+			return hasParam(field.Type, path[1:])
+		}
+		return false
+	case reflect.Slice:
+		if len(path) == 0 {
+			return false
+		}
+		if _, err := strconv.Atoi(path[0]); err != nil {
+			return false
+		}
+		return hasParam(t.Elem(), path[1:])
+	default:
+		return len(path) == 0
+	}
+}
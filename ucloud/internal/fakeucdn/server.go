@@ -0,0 +1,434 @@
+// Package fakeucdn implements an in-process fake of the UCloud CDN API.
+//
+// The server speaks the same signed form-encoded action protocol as
+// https://api.ucloud.cn, so the provider can be pointed at it through
+// `base_url` to run acceptance tests without credentials. Params that an
+// action does not document are rejected, so that the provider can't rely on
+// fields which the real API ignores.
+package fakeucdn
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+	"github.com/ucloud/ucloud-sdk-go/ucloud/auth"
+)
+
+const (
+	PublicKey  = "fake-public-key"
+	PrivateKey = "fake-private-key"
+)
+
+const (
+	RetCodeInvalidSignature = 172
+	RetCodeMissingAction    = 160
+	RetCodeInvalidParameter = 230
+	RetCodeNotFound         = 44002
+	RetCodeAlreadyExists    = 44003
+)
+
+type actionHandler func(s *Server, projectId string, values url.Values) (interface{}, error)
+
+// action handles requests whose params are fields of params.
+type action struct {
+	handler actionHandler
+	params  interface{}
+}
+
+type actionError struct {
+	retCode int
+	message string
+}
+
+func (e *actionError) Error() string {
+	return e.message
+}
+
+func errorf(retCode int, format string, a ...interface{}) error {
+	return &actionError{retCode: retCode, message: fmt.Sprintf(format, a...)}
+}
+
+// Server is a stateful fake of UCloud CDN API.
+type Server struct {
+	*httptest.Server
+
+	// Count of GetUcdnDomainConfig calls that a domain stays in a
+	// transitional status before moving to the target status.
+	PendingPolls int
+
+	// Omit the content of certificates from GetCertificateV2, so that only
+	// their metadata is returned.
+	HideCertificateContent bool
+
+	mu           sync.Mutex
+	seq          int
+	domains      map[string]*domain
+	certificates map[certificateKey]*certificate
+	tasks        map[string]*task
+	auditFail    map[string]bool
+	failedUrls   map[string]bool
+	failedTasks  map[string]bool
+	actionLog    []string
+	actions      map[string]action
+}
+
+func NewServer() *Server {
+	s := &Server{
+		PendingPolls: 1,
+		domains:      make(map[string]*domain),
+		certificates: make(map[certificateKey]*certificate),
+		tasks:        make(map[string]*task),
+		auditFail:    make(map[string]bool),
+		failedUrls:   make(map[string]bool),
+		failedTasks:  make(map[string]bool),
+		actions: map[string]action{
+			"BatchCreateNewUcdnDomain":         {(*Server).batchCreateNewUcdnDomain, batchCreateNewUcdnDomainParams{}},
+			"UpdateUcdnDomainConfig":           {(*Server).updateUcdnDomainConfig, updateUcdnDomainConfigParams{}},
+			"GetUcdnDomainConfig":              {(*Server).getUcdnDomainConfig, ucdn.GetUcdnDomainConfigRequest{}},
+			"UpdateUcdnDomainStatus":           {(*Server).updateUcdnDomainStatus, ucdn.UpdateUcdnDomainStatusRequest{}},
+			"UpdateUcdnDomainHttpsConfig":      {(*Server).updateUcdnDomainHttpsConfig, updateUcdnDomainHttpsConfigParams{}},
+			"AddCertificate":                   {(*Server).addCertificate, ucdn.AddCertificateRequest{}},
+			"GetCertificateV2":                 {(*Server).getCertificateV2, ucdn.GetCertificateV2Request{}},
+			"DeleteCertificate":                {(*Server).deleteCertificate, ucdn.DeleteCertificateRequest{}},
+			"RefreshNewUcdnDomainCache":        {(*Server).refreshNewUcdnDomainCache, ucdn.RefreshNewUcdnDomainCacheRequest{}},
+			"DescribeNewUcdnRefreshCacheTask":  {(*Server).describeNewUcdnRefreshCacheTask, ucdn.DescribeNewUcdnRefreshCacheTaskRequest{}},
+			"PrefetchNewUcdnDomainCache":       {(*Server).prefetchNewUcdnDomainCache, ucdn.PrefetchNewUcdnDomainCacheRequest{}},
+			"DescribeNewUcdnPrefetchCacheTask": {(*Server).describeNewUcdnPrefetchCacheTask, ucdn.DescribeNewUcdnPrefetchCacheTaskRequest{}},
+		},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// FailAudit makes the audit of domain fail after it is created.
+func (s *Server) FailAudit(domain string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auditFail[domain] = true
+}
+
+// FailUrl makes the refresh and prefetch of url fail.
+func (s *Server) FailUrl(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failedUrls[url] = true
+}
+
+// FailTask makes the refresh and prefetch tasks of url fail as a whole,
+// while each of their urls is reported as succeeded.
+func (s *Server) FailTask(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failedTasks[url] = true
+}
+
+// SetPendingPolls sets PendingPolls while the server may be serving
+// requests.
+func (s *Server) SetPendingPolls(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.PendingPolls = n
+}
+
+// Actions returns the actions that have been invoked, in the order they are
+// invoked.
+func (s *Server) Actions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.actionLog...)
+}
+
+// Domain returns a copy of the domain config with domainId.
+func (s *Server) Domain(domainId string) (api.DomainConfigInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.domains[domainId]
+	if !ok {
+		return api.DomainConfigInfo{}, false
+	}
+	return d.config, true
+}
+
+// DomainProject returns the id of the project that domain belongs to.
+func (s *Server) DomainProject(domainId string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.domains[domainId]
+	if !ok {
+		return "", false
+	}
+	return d.projectId, true
+}
+
+// Certificate returns a copy of the certificate with name in project.
+func (s *Server) Certificate(projectId, name string) (ucdn.CertList, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.certificates[certificateKey{projectId, name}]
+	if !ok {
+		return ucdn.CertList{}, false
+	}
+	return s.certList(c), true
+}
+
+// CreateDomain creates an enabled domain in project, as if it is created in
+// UCloud console, and returns its id.
+func (s *Server) CreateDomain(projectId, name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := &domain{
+		projectId: projectId,
+		config: api.DomainConfigInfo{
+			DomainId:          s.nextId("ucdn"),
+			Domain:            name,
+			AreaCode:          "cn",
+			CdnType:           "web",
+			Tag:               "Default",
+			Status:            api.DomainStatusEnable,
+			HttpsStatusCn:     "disable",
+			HttpsStatusAbroad: "disable",
+		},
+	}
+	d.config.Cname = d.config.DomainId + ".ucloud.fake"
+	s.domains[d.config.DomainId] = d
+	return d.config.DomainId
+}
+
+// SetHttps enables HTTPS of domain in area ("cn" or "abroad") with
+// certName, or disables it if certName is empty.
+func (s *Server) SetHttps(domainId, area, certName string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.domains[domainId]
+	if !ok {
+		return false
+	}
+	status := "enable"
+	if certName == "" {
+		status = "disable"
+	}
+	switch area {
+	case "cn":
+		d.config.HttpsStatusCn, d.config.CertNameCn = status, certName
+	case "abroad":
+		d.config.HttpsStatusAbroad, d.config.CertNameAbroad = status, certName
+	default:
+		return false
+	}
+	return true
+}
+
+// SetHttpsStatus sets the HTTPS status of domain in area ("cn" or "abroad")
+// while keeping its certificate, as if HTTPS is in a transitional status.
+func (s *Server) SetHttpsStatus(domainId, area, status string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.domains[domainId]
+	if !ok {
+		return false
+	}
+	switch area {
+	case "cn":
+		d.config.HttpsStatusCn = status
+	case "abroad":
+		d.config.HttpsStatusAbroad = status
+	default:
+		return false
+	}
+	return true
+}
+
+// SetCacheKeyList replaces the cache key rules of domain, as if they are
+// changed in UCloud console.
+func (s *Server) SetCacheKeyList(domainId string, cacheKeyList []ucdn.CacheKeyInfo) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.domains[domainId]
+	if !ok {
+		return false
+	}
+	d.config.CacheConf.CacheKeyList = cacheKeyList
+	return true
+}
+
+// ReplaceCertificate replaces the content of certificate with name in
+// project, as if it is changed in UCloud console.
+func (s *Server) ReplaceCertificate(projectId, name, userCert, caCert string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.certificates[certificateKey{projectId, name}]
+	if !ok {
+		return false
+	}
+	c.userCert = userCert
+	c.caCert = caCert
+	return true
+}
+
+func (s *Server) nextId(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%08d", prefix, s.seq)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	action := r.PostForm.Get("Action")
+	body := map[string]interface{}{
+		"Action":  action + "Response",
+		"RetCode": 0,
+	}
+
+	result, err := s.invoke(action, r.PostForm)
+	if err != nil {
+		retCode := RetCodeInvalidParameter
+		if aErr, ok := err.(*actionError); ok {
+			retCode = aErr.retCode
+		}
+		body["RetCode"] = retCode
+		body["Message"] = err.Error()
+	} else if result != nil {
+		fields, err := toMap(result)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for k, v := range fields {
+			body[k] = v
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (s *Server) invoke(action string, values url.Values) (interface{}, error) {
+	if err := verifySignature(values); err != nil {
+		return nil, err
+	}
+	a, ok := s.actions[action]
+	if !ok {
+		return nil, errorf(RetCodeMissingAction, "Action [%s] not found", action)
+	}
+	if err := checkParams(values, a.params); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.actionLog = append(s.actionLog, action)
+	return a.handler(s, values.Get("ProjectId"), values)
+}
+
+func verifySignature(values url.Values) error {
+	if values.Get("PublicKey") != PublicKey {
+		return errorf(RetCodeInvalidSignature, "Verify ac error")
+	}
+	params := make(map[string]interface{})
+	for k := range values {
+		if k != "Signature" {
+			params[k] = values.Get(k)
+		}
+	}
+	if auth.CalculateSignature(params, PrivateKey).Sign != values.Get("Signature") {
+		return errorf(RetCodeInvalidSignature, "Verify ac error")
+	}
+	return nil
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	err = json.Unmarshal(b, &m)
+	return m, err
+}
+
+// decodeForm fills v with the form values encoded by the UCloud SDK,
+// nested fields and list items are flattened as `Field.N.SubField`.
+func decodeForm(values url.Values, v interface{}) error {
+	return decodeValue(values, "", reflect.ValueOf(v).Elem())
+}
+
+func decodeValue(values url.Values, key string, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !hasKey(values, key) {
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(values, key, v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || field.Anonymous {
+				continue
+			}
+			if err := decodeValue(values, joinKey(key, field.Name), v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for n := 0; hasKey(values, joinKey(key, strconv.Itoa(n))); n++ {
+			item := reflect.New(v.Type().Elem()).Elem()
+			if err := decodeValue(values, joinKey(key, strconv.Itoa(n)), item); err != nil {
+				return err
+			}
+			v.Set(reflect.Append(v, item))
+		}
+	case reflect.String:
+		v.SetString(values.Get(key))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s := values.Get(key); s != "" {
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return errorf(RetCodeInvalidParameter, "Params [%s] not available", key)
+			}
+			v.SetInt(n)
+		}
+	case reflect.Bool:
+		if s := values.Get(key); s != "" {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return errorf(RetCodeInvalidParameter, "Params [%s] not available", key)
+			}
+			v.SetBool(b)
+		}
+	}
+	return nil
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func hasKey(values url.Values, key string) bool {
+	if _, ok := values[key]; ok {
+		return true
+	}
+	for k := range values {
+		if strings.HasPrefix(k, key+".") {
+			return true
+		}
+	}
+	return false
+}
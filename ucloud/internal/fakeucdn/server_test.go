@@ -0,0 +1,328 @@
+package fakeucdn_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/internal/fakeucdn"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+	"github.com/ucloud/ucloud-sdk-go/ucloud"
+	"github.com/ucloud/ucloud-sdk-go/ucloud/auth"
+	"github.com/ucloud/ucloud-sdk-go/ucloud/request"
+	"github.com/ucloud/ucloud-sdk-go/ucloud/response"
+)
+
+func newClient(t *testing.T, s *fakeucdn.Server, privateKey string) *ucdn.UCDNClient {
+	t.Helper()
+	cfg := ucloud.Config{
+		BaseUrl:   s.URL,
+		Region:    "cn-bj2",
+		Zone:      "cn-bj2-02",
+		ProjectId: "org-test",
+	}
+	keys := auth.Credential{
+		PublicKey:  fakeucdn.PublicKey,
+		PrivateKey: privateKey,
+	}
+	return ucdn.NewClient(&cfg, &keys)
+}
+
+func createDomain(t *testing.T, client *ucdn.UCDNClient, domain string) string {
+	t.Helper()
+	areaCode, cdnType := "cn", "web"
+	req := &api.CreateCdnDomainRequest{
+		CommonBase: request.CommonBase{
+			ProjectId: &client.GetConfig().ProjectId,
+		},
+		DomainList: []api.CreateDomainConfig{{
+			Domain:     domain,
+			OriginIp:   []string{"1.1.1.1"},
+			OriginHost: domain,
+			TestUrl:    "http://" + domain + "/",
+			AreaCode:   &areaCode,
+			CdnType:    &cdnType,
+		}},
+	}
+	var resp api.CreateCdnDomainResponse
+	if err := client.InvokeAction("BatchCreateNewUcdnDomain", req, &resp); err != nil {
+		t.Fatalf("BatchCreateNewUcdnDomain: %v", err)
+	}
+	if len(resp.DomainList) != 1 || resp.DomainList[0].RetCode != 0 {
+		t.Fatalf("unexpected response: %+v", resp.DomainList)
+	}
+	return resp.DomainList[0].DomainId
+}
+
+func TestSignature(t *testing.T) {
+	s := fakeucdn.NewServer()
+	defer s.Close()
+
+	_, err := api.GetCertificates(context.Background(), newClient(t, s, "wrong-key"), "org-test")
+	if err == nil {
+		t.Fatal("expected error with wrong private key")
+	}
+	_, err = api.GetCertificates(context.Background(), newClient(t, s, fakeucdn.PrivateKey), "org-test")
+	if err != nil {
+		t.Fatalf("GetCertificates: %v", err)
+	}
+}
+
+func TestDomainLifecycle(t *testing.T) {
+	ctx := context.Background()
+	s := fakeucdn.NewServer()
+	defer s.Close()
+	client := newClient(t, s, fakeucdn.PrivateKey)
+
+	domainId := createDomain(t, client, "test.example.com")
+	status, err := api.WaitForDomainStatus(ctx, client, client.GetConfig().ProjectId, domainId, []string{api.DomainStatusEnable})
+	if err != nil || status != api.DomainStatusEnable {
+		t.Fatalf("WaitForDomainStatus: %s, %v", status, err)
+	}
+
+	port, referType := int64(443), 1
+	updateReq := &api.UpdateCdnDomainRequest{
+		CommonBase: request.CommonBase{
+			ProjectId: &client.GetConfig().ProjectId,
+		},
+		DomainList: []api.UpdateCdnDomainConfig{{DomainId: domainId}},
+	}
+	updateReq.DomainList[0].OriginConf.OriginPort = &port
+	updateReq.DomainList[0].AccessControlConf.IpBlackList = []string{"10.0.0.1"}
+	updateReq.DomainList[0].AccessControlConf.ReferConf.ReferType = &referType
+	updateReq.DomainList[0].AccessControlConf.ReferConf.ReferList = []string{"example.org"}
+	updateReq.DomainList[0].AccessControlConf.EnableRefer = true
+	if err := api.UpdateCdnDomain(ctx, client, updateReq); err != nil {
+		t.Fatalf("UpdateCdnDomain: %v", err)
+	}
+
+	config, err := api.GetUcdnDomainConfig(ctx, client, client.GetConfig().ProjectId, domainId)
+	if err != nil {
+		t.Fatalf("GetUcdnDomainConfig: %v", err)
+	}
+	if config.OriginConf.OriginPort != 443 ||
+		len(config.AccessControlConf.IpBlackList) != 1 ||
+		config.AccessControlConf.ReferConf.ReferType != 1 ||
+		len(config.AccessControlConf.ReferConf.ReferList) != 1 {
+		t.Fatalf("unexpected config: %+v", config)
+	}
+
+	if err := api.DeleteDomain(ctx, client, client.GetConfig().ProjectId, domainId); err != nil {
+		t.Fatalf("DeleteDomain: %v", err)
+	}
+	if _, ok := s.Domain(domainId); ok {
+		t.Fatal("domain still exists after deletion")
+	}
+}
+
+func TestCacheKeyList(t *testing.T) {
+	ctx := context.Background()
+	s := fakeucdn.NewServer()
+	defer s.Close()
+	client := newClient(t, s, fakeucdn.PrivateKey)
+	domainId := createDomain(t, client, "test.example.com")
+
+	updateReq := &api.UpdateCdnDomainRequest{
+		CommonBase: request.CommonBase{
+			ProjectId: &client.GetConfig().ProjectId,
+		},
+		DomainList: []api.UpdateCdnDomainConfig{{DomainId: domainId}},
+	}
+	want := []ucdn.CacheKeyInfo{
+		{PathPattern: "/static/", QueryString: "$querystring", Ignore: true},
+		{PathPattern: "/api/", QueryString: "$a+$b", Ignore: false},
+	}
+	updateReq.DomainList[0].CacheConf.CacheKeyList = want
+	if err := api.UpdateCdnDomain(ctx, client, updateReq); err != nil {
+		t.Fatalf("UpdateCdnDomain: %v", err)
+	}
+
+	config, err := api.GetUcdnDomainConfig(ctx, client, client.GetConfig().ProjectId, domainId)
+	if err != nil {
+		t.Fatalf("GetUcdnDomainConfig: %v", err)
+	}
+	if !reflect.DeepEqual(config.CacheConf.CacheKeyList, want) {
+		t.Fatalf("expected cache key list %+v, got %+v", want, config.CacheConf.CacheKeyList)
+	}
+}
+
+func TestCertificateProjects(t *testing.T) {
+	ctx := context.Background()
+	s := fakeucdn.NewServer()
+	defer s.Close()
+	client := newClient(t, s, fakeucdn.PrivateKey)
+	userCert := "-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n"
+
+	for _, projectId := range []string{"org-test", "org-other"} {
+		if err := api.AddCertificate(ctx, client, projectId, "test-cert", userCert, "key", ""); err != nil {
+			t.Fatalf("AddCertificate in %s: %v", projectId, err)
+		}
+	}
+	if err := api.AddCertificate(ctx, client, "org-test", "test-cert", userCert, "key", ""); err == nil {
+		t.Fatal("expected error adding a certificate that exists in the project")
+	}
+
+	if err := api.DeleteCertificate(ctx, client, "org-other", "test-cert"); err != nil {
+		t.Fatalf("DeleteCertificate: %v", err)
+	}
+	if _, ok := s.Certificate("org-test", "test-cert"); !ok {
+		t.Fatal("certificate of another project is deleted")
+	}
+	if _, ok := s.Certificate("org-other", "test-cert"); ok {
+		t.Fatal("certificate still exists after deletion")
+	}
+}
+
+func TestAuditFail(t *testing.T) {
+	s := fakeucdn.NewServer()
+	defer s.Close()
+	s.FailAudit("fail.example.com")
+	client := newClient(t, s, fakeucdn.PrivateKey)
+
+	domainId := createDomain(t, client, "fail.example.com")
+	status, err := api.WaitForDomainStatus(context.Background(), client, client.GetConfig().ProjectId, domainId,
+		[]string{api.DomainStatusEnable, api.DomainStatusCheckFail})
+	if err != nil || status != api.DomainStatusCheckFail {
+		t.Fatalf("WaitForDomainStatus: %s, %v", status, err)
+	}
+}
+
+func TestCacheTask(t *testing.T) {
+	ctx := context.Background()
+	s := fakeucdn.NewServer()
+	defer s.Close()
+	s.FailUrl("http://test.example.com/b")
+	client := newClient(t, s, fakeucdn.PrivateKey)
+
+	taskId, err := api.RefreshDomainCache(ctx, client, client.GetConfig().ProjectId, "file", []string{"http://test.example.com/a"})
+	if err != nil {
+		t.Fatalf("RefreshDomainCache: %v", err)
+	}
+	if _, err := api.WaitForRefreshCacheTask(ctx, client, client.GetConfig().ProjectId, taskId); err != nil {
+		t.Fatalf("WaitForRefreshCacheTask: %v", err)
+	}
+
+	taskId, err = api.PrefetchDomainCache(ctx, client, client.GetConfig().ProjectId, []string{"http://test.example.com/a", "http://test.example.com/b"})
+	if err != nil {
+		t.Fatalf("PrefetchDomainCache: %v", err)
+	}
+	task, err := api.WaitForPrefetchCacheTask(ctx, client, client.GetConfig().ProjectId, taskId)
+	if err == nil || task == nil || task.Status != api.CacheTaskStatusFailure {
+		t.Fatalf("expected prefetch task to fail, got %+v, %v", task, err)
+	}
+}
+
+func TestCacheTaskFailure(t *testing.T) {
+	ctx := context.Background()
+	s := fakeucdn.NewServer()
+	defer s.Close()
+	s.FailTask("http://test.example.com/a")
+	client := newClient(t, s, fakeucdn.PrivateKey)
+
+	taskId, err := api.PrefetchDomainCache(ctx, client, client.GetConfig().ProjectId, []string{"http://test.example.com/a"})
+	if err != nil {
+		t.Fatalf("PrefetchDomainCache: %v", err)
+	}
+	task, err := api.WaitForPrefetchCacheTask(ctx, client, client.GetConfig().ProjectId, taskId)
+	if err == nil || task == nil || task.Status != api.CacheTaskStatusFailure {
+		t.Fatalf("expected prefetch task to fail, got %+v, %v", task, err)
+	}
+	for _, url := range task.UrlLists {
+		if url.Status != api.CacheTaskStatusSuccess {
+			t.Fatalf("expected url %s to succeed, got %s", url.Url, url.Status)
+		}
+	}
+}
+
+func TestDomainStatus(t *testing.T) {
+	ctx := context.Background()
+	s := fakeucdn.NewServer()
+	defer s.Close()
+	client := newClient(t, s, fakeucdn.PrivateKey)
+
+	domainId := createDomain(t, client, "test.example.com")
+	if _, err := api.WaitForDomainStatus(ctx, client, client.GetConfig().ProjectId, domainId, []string{api.DomainStatusEnable}); err != nil {
+		t.Fatalf("WaitForDomainStatus: %v", err)
+	}
+	status, err := api.UpdateDomainStatus(ctx, client, client.GetConfig().ProjectId, domainId, api.DomainStatusDisable)
+	if err != nil {
+		t.Fatalf("UpdateDomainStatus: %v", err)
+	}
+	if status != api.DomainStatusDisable {
+		t.Fatalf("expected status %s, got %s", api.DomainStatusDisable, status)
+	}
+
+	// Updating config of a disabled domain keeps it disabled.
+	port := int64(8080)
+	updateReq := &api.UpdateCdnDomainRequest{
+		CommonBase: request.CommonBase{
+			ProjectId: &client.GetConfig().ProjectId,
+		},
+		DomainList: []api.UpdateCdnDomainConfig{{DomainId: domainId}},
+	}
+	updateReq.DomainList[0].OriginConf.OriginPort = &port
+	if err := api.UpdateCdnDomain(ctx, client, updateReq); err != nil {
+		t.Fatalf("UpdateCdnDomain: %v", err)
+	}
+	if domain, _ := s.Domain(domainId); domain.Status != api.DomainStatusDisable {
+		t.Fatalf("expected domain to be disabled, got %s", domain.Status)
+	}
+
+	if _, err := api.UpdateDomainStatus(ctx, client, client.GetConfig().ProjectId, domainId, api.DomainStatusEnable); err != nil {
+		t.Fatalf("UpdateDomainStatus: %v", err)
+	}
+	if domain, _ := s.Domain(domainId); domain.Status != api.DomainStatusEnable {
+		t.Fatalf("expected domain to be enabled, got %s", domain.Status)
+	}
+}
+
+func TestUnknownParams(t *testing.T) {
+	s := fakeucdn.NewServer()
+	defer s.Close()
+	client := newClient(t, s, fakeucdn.PrivateKey)
+	domainId := createDomain(t, client, "test.example.com")
+
+	tests := []struct {
+		name   string
+		action string
+		req    request.Common
+		param  string
+	}{
+		{
+			name:   "top level",
+			action: "UpdateUcdnDomainHttpsConfig",
+			req: &struct {
+				request.CommonBase
+				DomainId      string
+				MinTlsVersion string
+			}{DomainId: domainId, MinTlsVersion: "TLSv1.2"},
+			param: "MinTlsVersion",
+		},
+		{
+			name:   "nested",
+			action: "UpdateUcdnDomainConfig",
+			req: &struct {
+				request.CommonBase
+				DomainList []struct {
+					DomainId     string
+					AdvancedConf struct{ Gzip bool }
+				}
+			}{DomainList: []struct {
+				DomainId     string
+				AdvancedConf struct{ Gzip bool }
+			}{{DomainId: domainId}}},
+			param: "DomainList.0.AdvancedConf.Gzip",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp response.CommonBase
+			err := client.InvokeAction(tt.action, tt.req, &resp)
+			if err == nil || resp.RetCode != fakeucdn.RetCodeInvalidParameter || !strings.Contains(resp.Message, tt.param) {
+				t.Fatalf("expected invalid parameter %s, got %d: %v", tt.param, resp.RetCode, err)
+			}
+		})
+	}
+}
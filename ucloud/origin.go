@@ -0,0 +1,63 @@
+package ucloud
+
+import (
+	"net"
+	"strings"
+)
+
+// Types of origin. UCloud has no parameter for the type of origin, IPs and
+// hostnames are both sent in `OriginIp`, the type is only inferred from them.
+const (
+	originTypeIp     = "ip"
+	originTypeDomain = "domain"
+	originTypeUs3    = "us3"
+)
+
+// us3BucketDomainSuffix is the suffix of domains of US3 (formerly UFile)
+// buckets, e.g. `bucket.cn-bj.ufileos.com`.
+const us3BucketDomainSuffix = ".ufileos.com"
+
+// isHostname reports whether s is a valid DNS hostname, which is not an IP
+// address. A top-level domain is never numeric, so a name whose last label is
+// all digits, e.g. a mistyped IP like `10.0.0.256`, is rejected.
+func isHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if len(s) == 0 || len(s) > 253 || net.ParseIP(s) != nil {
+		return false
+	}
+	labels := strings.Split(s, ".")
+	if strings.Trim(labels[len(labels)-1], "0123456789") == "" {
+		return false
+	}
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isUs3BucketDomain reports whether s is the domain of a US3 bucket.
+func isUs3BucketDomain(s string) bool {
+	s = strings.ToLower(strings.TrimSuffix(s, "."))
+	return isHostname(s) && strings.HasSuffix(s, us3BucketDomainSuffix) && len(s) > len(us3BucketDomainSuffix)
+}
+
+// originTypeOf infers the origin type from the addresses of origin. Addresses
+// are assumed to be either all IPs or all hostnames.
+func originTypeOf(addrs []string) string {
+	if len(addrs) == 0 || net.ParseIP(addrs[0]) != nil {
+		return originTypeIp
+	}
+	for _, addr := range addrs {
+		if !isUs3BucketDomain(addr) {
+			return originTypeDomain
+		}
+	}
+	return originTypeUs3
+}
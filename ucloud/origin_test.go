@@ -0,0 +1,52 @@
+package ucloud
+
+import (
+	"testing"
+)
+
+func TestIsHostname(t *testing.T) {
+	cases := []struct {
+		s    string
+		want bool
+	}{
+		{"example.com", true},
+		{"origin-1.example.com.", true},
+		{"localhost", true},
+		{"1.1.1.1", false},
+		{"2001:db8::1", false},
+		{"-bad.example.com", false},
+		{"bad..example.com", false},
+		{"bad_label.example.com", false},
+		{"10.0.0.256", false},
+		{"1.2.3", false},
+		{"1.2.3.", false},
+		{"123", false},
+		{"1.example.com", true},
+		{"example.c0m", true},
+		{"", false},
+	}
+	for _, c := range cases {
+		if got := isHostname(c.s); got != c.want {
+			t.Errorf("isHostname(%q) = %v, want %v", c.s, got, c.want)
+		}
+	}
+}
+
+func TestOriginTypeOf(t *testing.T) {
+	cases := []struct {
+		addrs []string
+		want  string
+	}{
+		{[]string{"1.1.1.1", "2.2.2.2"}, originTypeIp},
+		{[]string{"origin.example.com"}, originTypeDomain},
+		{[]string{"bucket.cn-bj.ufileos.com"}, originTypeUs3},
+		{[]string{"bucket.cn-bj.ufileos.com", "origin.example.com"}, originTypeDomain},
+		{[]string{"ufileos.com"}, originTypeDomain},
+		{nil, originTypeIp},
+	}
+	for _, c := range cases {
+		if got := originTypeOf(c.addrs); got != c.want {
+			t.Errorf("originTypeOf(%v) = %q, want %q", c.addrs, got, c.want)
+		}
+	}
+}
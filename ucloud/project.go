@@ -0,0 +1,57 @@
+package ucloud
+
+import (
+	"strings"
+
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+)
+
+const projectIdDescription = "Id of the project.If the value is unset,`project_id` of provider is used."
+
+// projectIdResourceAttribute returns the schema of `project_id` of resources,
+// which overrides the project of provider. Resources can't be moved between
+// projects, so changing it requires replacement.
+func projectIdResourceAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: projectIdDescription,
+		Optional:    true,
+		Computed:    true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+}
+
+// projectIdDataSourceAttribute returns the schema of `project_id` of data
+// sources, which overrides the project of provider.
+func projectIdDataSourceAttribute() datasourceschema.StringAttribute {
+	return datasourceschema.StringAttribute{
+		Description: projectIdDescription,
+		Optional:    true,
+		Computed:    true,
+	}
+}
+
+// projectIdOf returns projectId if it is set, or the project of provider
+// otherwise.
+func projectIdOf(client *ucdn.UCDNClient, projectId types.String) string {
+	if projectId.IsNull() || projectId.IsUnknown() || projectId.ValueString() == "" {
+		return client.GetConfig().ProjectId
+	}
+	return projectId.ValueString()
+}
+
+// parseImportId splits an import id in the format `[<project_id>/]<id>`. The
+// project of provider is used if project_id is omitted.
+func parseImportId(client *ucdn.UCDNClient, importId string) (string, string) {
+	if projectId, id, ok := strings.Cut(importId, "/"); ok {
+		return projectId, id
+	}
+	return client.GetConfig().ProjectId, importId
+}
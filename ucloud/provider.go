@@ -2,7 +2,16 @@ package ucloud
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -11,6 +20,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/ucloud/ucloud-sdk-go/external"
 	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
 	"github.com/ucloud/ucloud-sdk-go/ucloud"
 	"github.com/ucloud/ucloud-sdk-go/ucloud/auth"
@@ -24,6 +34,14 @@ type ucloudProviderModel struct {
 	ProjectId  types.String `tfsdk:"project_id"`
 	PublicKey  types.String `tfsdk:"public_key"`
 	PrivateKey types.String `tfsdk:"private_key"`
+
+	SharedConfigFile      types.String `tfsdk:"shared_config_file"`
+	SharedCredentialsFile types.String `tfsdk:"shared_credentials_file"`
+	Profile               types.String `tfsdk:"profile"`
+
+	BaseUrl    types.String `tfsdk:"base_url"`
+	Insecure   types.Bool   `tfsdk:"insecure"`
+	CaCertFile types.String `tfsdk:"ca_cert_file"`
 }
 
 // Ensure the implementation satisfies the expected interfaces
@@ -74,6 +92,36 @@ func (p *ucloudProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"shared_config_file": schema.StringAttribute{
+				Description: "The path to the shared config file of UCloud CLI, which provides region, zone and project_id of profiles. " +
+					"May also be provided via UCLOUD_SHARED_CONFIG_FILE environment variable. Default is `~/.ucloud/config.json`",
+				Optional: true,
+			},
+			"shared_credentials_file": schema.StringAttribute{
+				Description: "The path to the shared credentials file of UCloud CLI, which provides public_key and private_key of profiles. " +
+					"May also be provided via UCLOUD_SHARED_CREDENTIAL_FILE environment variable. Default is `~/.ucloud/credential.json`",
+				Optional: true,
+			},
+			"profile": schema.StringAttribute{
+				Description: "The profile name in shared config file and shared credentials file. " +
+					"May also be provided via UCLOUD_PROFILE environment variable. A profile set explicitly must exist in the shared files. Default is `default`",
+				Optional: true,
+			},
+			"base_url": schema.StringAttribute{
+				Description: "The base url of Ucloud API, useful for private endpoints or a local stand-in API server. " +
+					"May also be provided via UCLOUD_BASE_URL environment variable. Default is `" + ApiEndpoint + "`",
+				Optional: true,
+			},
+			"insecure": schema.BoolAttribute{
+				Description: "Whether to skip the verification of TLS certificate of `base_url`. " +
+					"May also be provided via UCLOUD_INSECURE environment variable. Default is false",
+				Optional: true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: "The path to a PEM encoded CA bundle used to verify the TLS certificate of `base_url`. " +
+					"May also be provided via UCLOUD_CA_CERT_FILE environment variable.",
+				Optional: true,
+			},
 		},
 	}
 }
@@ -134,6 +182,60 @@ func (p *ucloudProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		)
 	}
 
+	if model.SharedConfigFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("shared_config_file"),
+			"Unknown SharedConfigFile",
+			"The provider cannot create the UCloud API client as there is an unknown configuration value for the"+
+				"shared_config_file. Set the value statically in the configuration, or use the UCLOUD_SHARED_CONFIG_FILE environment variable.",
+		)
+	}
+
+	if model.SharedCredentialsFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("shared_credentials_file"),
+			"Unknown SharedCredentialsFile",
+			"The provider cannot create the UCloud API client as there is an unknown configuration value for the"+
+				"shared_credentials_file. Set the value statically in the configuration, or use the UCLOUD_SHARED_CREDENTIAL_FILE environment variable.",
+		)
+	}
+
+	if model.Profile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("profile"),
+			"Unknown Profile",
+			"The provider cannot create the UCloud API client as there is an unknown configuration value for the"+
+				"profile. Set the value statically in the configuration, or use the UCLOUD_PROFILE environment variable.",
+		)
+	}
+
+	if model.BaseUrl.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("base_url"),
+			"Unknown BaseUrl",
+			"The provider cannot create the UCloud API client as there is an unknown configuration value for the"+
+				"base_url. Set the value statically in the configuration, or use the UCLOUD_BASE_URL environment variable.",
+		)
+	}
+
+	if model.Insecure.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("insecure"),
+			"Unknown Insecure",
+			"The provider cannot create the UCloud API client as there is an unknown configuration value for the"+
+				"insecure. Set the value statically in the configuration, or use the UCLOUD_INSECURE environment variable.",
+		)
+	}
+
+	if model.CaCertFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ca_cert_file"),
+			"Unknown CaCertFile",
+			"The provider cannot create the UCloud API client as there is an unknown configuration value for the"+
+				"ca_cert_file. Set the value statically in the configuration, or use the UCLOUD_CA_CERT_FILE environment variable.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -143,7 +245,13 @@ func (p *ucloudProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		zone,
 		projectId,
 		publicKey,
-		privateKey string
+		privateKey,
+		sharedConfigFile,
+		sharedCredentialsFile,
+		profile,
+		baseUrl,
+		caCertFile string
+		insecure bool
 	)
 
 	// Default values to environment variables, but override
@@ -178,6 +286,134 @@ func (p *ucloudProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		privateKey = os.Getenv("UCLOUD_PRIVATE_KEY")
 	}
 
+	if !model.SharedConfigFile.IsNull() {
+		sharedConfigFile = model.SharedConfigFile.ValueString()
+	} else {
+		sharedConfigFile = os.Getenv("UCLOUD_SHARED_CONFIG_FILE")
+	}
+
+	if !model.SharedCredentialsFile.IsNull() {
+		sharedCredentialsFile = model.SharedCredentialsFile.ValueString()
+	} else {
+		sharedCredentialsFile = os.Getenv("UCLOUD_SHARED_CREDENTIAL_FILE")
+	}
+
+	if !model.Profile.IsNull() {
+		profile = model.Profile.ValueString()
+	} else {
+		profile = os.Getenv("UCLOUD_PROFILE")
+	}
+	// Only a profile selected explicitly must exist in shared files.
+	profileSelected := profile != ""
+	if profile == "" {
+		profile = external.DefaultProfile
+	}
+
+	if !model.BaseUrl.IsNull() {
+		baseUrl = model.BaseUrl.ValueString()
+	} else {
+		baseUrl = os.Getenv("UCLOUD_BASE_URL")
+	}
+
+	if !model.Insecure.IsNull() {
+		insecure = model.Insecure.ValueBool()
+	} else if v := os.Getenv("UCLOUD_INSECURE"); v != "" {
+		var err error
+		insecure, err = strconv.ParseBool(v)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("insecure"),
+				"Invalid Insecure",
+				"The provider cannot parse the UCLOUD_INSECURE environment variable as a boolean: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	if !model.CaCertFile.IsNull() {
+		caCertFile = model.CaCertFile.ValueString()
+	} else {
+		caCertFile = os.Getenv("UCLOUD_CA_CERT_FILE")
+	}
+
+	// Values which are neither configured nor provided by environment
+	// variables fall back to the profile in shared files.
+	sharedConfig, err := external.LoadUCloudConfigFile(expandHomeDir(sharedConfigFile), profile)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("shared_config_file"),
+			"Invalid Shared Config File",
+			"The provider cannot load the shared config file: "+err.Error(),
+		)
+		return
+	}
+	sharedCredential, err := external.LoadUCloudCredentialFile(expandHomeDir(sharedCredentialsFile), profile)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("shared_credentials_file"),
+			"Invalid Shared Credentials File",
+			"The provider cannot load the shared credentials file: "+err.Error(),
+		)
+		return
+	}
+	if profileSelected {
+		for _, sharedFile := range []struct {
+			name        string
+			defaultName string
+		}{
+			{sharedConfigFile, external.DefaultSharedConfigFile()},
+			{sharedCredentialsFile, external.DefaultSharedCredentialsFile()},
+		} {
+			name := expandHomeDir(sharedFile.name)
+			if name == "" {
+				name = sharedFile.defaultName
+			}
+			found, err := sharedFileHasProfile(name, profile)
+			// The default shared files are optional.
+			if err != nil && sharedFile.name == "" && os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("profile"),
+					"Invalid Shared File",
+					"The provider cannot load the shared file: "+err.Error(),
+				)
+				return
+			}
+			if !found {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("profile"),
+					"Profile Not Found",
+					fmt.Sprintf("The provider cannot create the UCloud API client as profile %q not found in %s.", profile, name),
+				)
+				return
+			}
+		}
+	}
+
+	if region == "" {
+		region = sharedConfig.Region
+	}
+	if zone == "" {
+		zone = sharedConfig.Zone
+	}
+	if projectId == "" {
+		projectId = sharedConfig.ProjectId
+	}
+	if publicKey == "" {
+		publicKey = sharedCredential.PublicKey
+	}
+	if privateKey == "" {
+		privateKey = sharedCredential.PrivateKey
+	}
+	if baseUrl == "" {
+		baseUrl = sharedConfig.BaseUrl
+	}
+	if baseUrl == "" {
+		baseUrl = ApiEndpoint
+	}
+
 	// If any of the expected configuration are missing, return
 	// errors with provider-specific guidance.
 	if region == "" {
@@ -244,8 +480,17 @@ func (p *ucloudProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	if _, err := url.ParseRequestURI(baseUrl); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("base_url"),
+			"Invalid BaseUrl",
+			"The provider cannot create the UCloud API client as the base_url is not a valid url: "+err.Error(),
+		)
+		return
+	}
+
 	cfg := ucloud.Config{
-		BaseUrl:   ApiEndpoint,
+		BaseUrl:   baseUrl,
 		Region:    region,
 		Zone:      zone,
 		ProjectId: projectId,
@@ -255,6 +500,18 @@ func (p *ucloudProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		PrivateKey: privateKey,
 	}
 	client := ucdn.NewClient(&cfg, &keys)
+	if insecure || caCertFile != "" {
+		transport, err := newTLSTransport(insecure, expandHomeDir(caCertFile))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ca_cert_file"),
+				"Invalid CaCertFile",
+				"The provider cannot load the CA bundle: "+err.Error(),
+			)
+			return
+		}
+		client.SetTransport(transport)
+	}
 
 	// UCloud clients wrapper
 	ucloudClients := ucloudClients{
@@ -265,9 +522,70 @@ func (p *ucloudProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	resp.ResourceData = ucloudClients
 }
 
+// Expand the leading `~` of path to the home directory of current user.
+func expandHomeDir(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~"+string(os.PathSeparator)) {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, p[1:])
+}
+
+// sharedFileHasProfile reports whether the shared config or credentials file
+// of UCloud CLI contains profile.
+func sharedFileHasProfile(name, profile string) (bool, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return false, err
+	}
+	var profiles []struct {
+		Profile string `json:"profile"`
+	}
+	if err := json.Unmarshal(b, &profiles); err != nil {
+		return false, fmt.Errorf("fail to parse %s: %w", name, err)
+	}
+	for _, p := range profiles {
+		if p.Profile == profile {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Build a transport for base_url that trusts the CA bundle in caCertFile
+// or skips the verification of TLS certificate.
+func newTLSTransport(insecure bool, caCertFile string) (*http.Transport, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecure,
+	}
+	if caCertFile != "" {
+		pem, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return nil, err
+		}
+		certPool, err := x509.SystemCertPool()
+		if err != nil {
+			certPool = x509.NewCertPool()
+		}
+		if !certPool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", caCertFile)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
 func (p *ucloudProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewCertDataSource,
+		NewCdnDomainsDataSource,
+		NewCdnDomainDataSource,
 	}
 }
 
@@ -276,5 +594,7 @@ func (p *ucloudProvider) Resources(_ context.Context) []func() resource.Resource
 		NewSslCertificateResource,
 		NewCdnDomainResource,
 		NewCdnDomainSslResource,
+		NewCdnCacheRefreshResource,
+		NewCdnCachePrefetchResource,
 	}
 }
@@ -0,0 +1,466 @@
+package ucloud
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/internal/fakeucdn"
+)
+
+// testAccProtoV6ProviderFactories are used to instantiate a provider during
+// acceptance testing.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"st-ucloud": providerserver.NewProtocol6WithError(New()),
+}
+
+func TestProviderResources(t *testing.T) {
+	registered := make(map[string]bool)
+	for _, newResource := range New().Resources(context.Background()) {
+		var resp resource.MetadataResponse
+		newResource().Metadata(context.Background(), resource.MetadataRequest{ProviderTypeName: "st-ucloud"}, &resp)
+		registered[resp.TypeName] = true
+	}
+	for _, typeName := range []string{
+		"st-ucloud_ssl_certificate",
+		"st-ucloud_cdn_domain",
+		"st-ucloud_cdn_domain_ssl_association",
+		"st-ucloud_cdn_cache_refresh",
+		"st-ucloud_cdn_cache_prefetch",
+	} {
+		if !registered[typeName] {
+			t.Errorf("resource %s is not registered", typeName)
+		}
+	}
+}
+
+func TestProviderConfigureProfile(t *testing.T) {
+	const sharedConfig = `[
+  {"profile": "default", "region": "cn-bj2", "zone": "cn-bj2-02", "project_id": "org-default"},
+  {"profile": "dev", "region": "cn-sh2", "zone": "cn-sh2-01", "project_id": "org-dev"}
+]`
+	const sharedCredential = `[
+  {"profile": "default", "public_key": "default-public", "private_key": "default-private"},
+  {"profile": "dev", "public_key": "dev-public", "private_key": "dev-private"}
+]`
+
+	cases := []struct {
+		name          string
+		config        map[string]string
+		env           map[string]string
+		wantProjectId string
+		wantPublicKey string
+		wantError     string
+	}{
+		{
+			name:          "default profile",
+			wantProjectId: "org-default",
+			wantPublicKey: "default-public",
+		},
+		{
+			name:          "profile attribute",
+			config:        map[string]string{"profile": "dev"},
+			wantProjectId: "org-dev",
+			wantPublicKey: "dev-public",
+		},
+		{
+			name:          "UCLOUD_PROFILE",
+			env:           map[string]string{"UCLOUD_PROFILE": "dev"},
+			wantProjectId: "org-dev",
+			wantPublicKey: "dev-public",
+		},
+		{
+			name:          "profile attribute overrides UCLOUD_PROFILE",
+			config:        map[string]string{"profile": "default"},
+			env:           map[string]string{"UCLOUD_PROFILE": "dev"},
+			wantProjectId: "org-default",
+			wantPublicKey: "default-public",
+		},
+		{
+			name:          "attributes override profile",
+			config:        map[string]string{"profile": "dev", "project_id": "org-test", "public_key": "test-public"},
+			wantProjectId: "org-test",
+			wantPublicKey: "test-public",
+		},
+		{
+			name:      "profile not found",
+			config:    map[string]string{"profile": "prod"},
+			wantError: `profile "prod" not found in`,
+		},
+		{
+			name:      "UCLOUD_PROFILE not found",
+			env:       map[string]string{"UCLOUD_PROFILE": "prod"},
+			wantError: `profile "prod" not found in`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			home := testProviderEnv(t)
+			configFile := filepath.Join(home, "config.json")
+			credentialFile := filepath.Join(home, "credential.json")
+			testWriteFile(t, configFile, sharedConfig)
+			testWriteFile(t, credentialFile, sharedCredential)
+			for k, v := range c.env {
+				t.Setenv(k, v)
+			}
+
+			config := map[string]string{
+				"shared_config_file":      configFile,
+				"shared_credentials_file": credentialFile,
+			}
+			for k, v := range c.config {
+				config[k] = v
+			}
+			resp := testProviderConfigure(t, config)
+			if c.wantError != "" {
+				if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), c.wantError) {
+					t.Fatalf("expected error %q, got %v", c.wantError, resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			client := resp.ResourceData.(ucloudClients).cdnClient
+			if got := client.GetConfig().ProjectId; got != c.wantProjectId {
+				t.Errorf("project id = %q, want %q", got, c.wantProjectId)
+			}
+			if got := client.GetCredential().PublicKey; got != c.wantPublicKey {
+				t.Errorf("public key = %q, want %q", got, c.wantPublicKey)
+			}
+		})
+	}
+}
+
+func TestProviderConfigureSharedFileInHomeDir(t *testing.T) {
+	home := testProviderEnv(t)
+	testWriteFile(t, filepath.Join(home, "ucloud", "config.json"),
+		`[{"profile": "default", "region": "cn-bj2", "zone": "cn-bj2-02", "project_id": "org-home"}]`)
+
+	resp := testProviderConfigure(t, map[string]string{
+		"shared_config_file": "~/ucloud/config.json",
+		"public_key":         "test-public",
+		"private_key":        "test-private",
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+	if got := resp.ResourceData.(ucloudClients).cdnClient.GetConfig().ProjectId; got != "org-home" {
+		t.Errorf("project id = %q, want %q", got, "org-home")
+	}
+}
+
+func TestExpandHomeDir(t *testing.T) {
+	home := testProviderEnv(t)
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"", ""},
+		{"~", home},
+		{"~/.ucloud/config.json", filepath.Join(home, ".ucloud", "config.json")},
+		{"/etc/ucloud/config.json", "/etc/ucloud/config.json"},
+		{"config.json", "config.json"},
+		{"~other/config.json", "~other/config.json"},
+	}
+	for _, c := range cases {
+		if got := expandHomeDir(c.path); got != c.want {
+			t.Errorf("expandHomeDir(%q) = %q, want %q", c.path, got, c.want)
+		}
+	}
+}
+
+func TestProviderConfigureBaseUrl(t *testing.T) {
+	cases := []struct {
+		name      string
+		config    map[string]string
+		env       map[string]string
+		want      string
+		wantError string
+	}{
+		{
+			name: "default",
+			want: ApiEndpoint,
+		},
+		{
+			name: "UCLOUD_BASE_URL",
+			env:  map[string]string{"UCLOUD_BASE_URL": "https://api.example.com"},
+			want: "https://api.example.com",
+		},
+		{
+			name:   "base_url overrides UCLOUD_BASE_URL",
+			config: map[string]string{"base_url": "https://private.example.com"},
+			env:    map[string]string{"UCLOUD_BASE_URL": "https://api.example.com"},
+			want:   "https://private.example.com",
+		},
+		{
+			name:      "invalid",
+			env:       map[string]string{"UCLOUD_BASE_URL": "api.example.com"},
+			wantError: "Invalid BaseUrl",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			testProviderEnv(t)
+			for k, v := range c.env {
+				t.Setenv(k, v)
+			}
+			resp := testProviderConfigure(t, testProviderCredentialConfig(c.config))
+			if c.wantError != "" {
+				testCheckAttributeError(t, resp.Diagnostics, path.Root("base_url"), c.wantError)
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if got := resp.ResourceData.(ucloudClients).cdnClient.GetConfig().BaseUrl; got != c.want {
+				t.Errorf("base url = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestProviderConfigureCaCertFile(t *testing.T) {
+	dir := t.TempDir()
+	invalidCaCertFile := filepath.Join(dir, "invalid.pem")
+	testWriteFile(t, invalidCaCertFile, "not a certificate")
+	caCertFile := filepath.Join(dir, "ca.pem")
+	cert, _ := testAccCertificate(t, "ca.example.com")
+	testWriteFile(t, caCertFile, cert)
+
+	cases := []struct {
+		name       string
+		caCertFile string
+		wantError  bool
+	}{
+		{"valid", caCertFile, false},
+		{"not found", filepath.Join(dir, "missing.pem"), true},
+		{"no certificate", invalidCaCertFile, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			testProviderEnv(t)
+			resp := testProviderConfigure(t, testProviderCredentialConfig(map[string]string{"ca_cert_file": c.caCertFile}))
+			if c.wantError {
+				testCheckAttributeError(t, resp.Diagnostics, path.Root("ca_cert_file"), "Invalid CaCertFile")
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestNewTLSTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	testWriteFile(t, caCertFile, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})))
+
+	cases := []struct {
+		name       string
+		insecure   bool
+		caCertFile string
+		wantError  bool
+	}{
+		{"untrusted", false, "", true},
+		{"insecure", true, "", false},
+		{"ca cert file", false, caCertFile, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			transport, err := newTLSTransport(c.insecure, c.caCertFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != c.wantError {
+				t.Errorf("error = %v, wantError %v", err, c.wantError)
+			}
+		})
+	}
+}
+
+// testProviderEnv isolates the provider from the environment variables and
+// shared files of current user, and returns the temporary home directory.
+func testProviderEnv(t *testing.T) string {
+	t.Helper()
+	for _, env := range os.Environ() {
+		if name, _, _ := strings.Cut(env, "="); strings.HasPrefix(name, "UCLOUD_") {
+			t.Setenv(name, "")
+		}
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	return home
+}
+
+// testProviderConfigure configures the provider with the string attributes
+// of config, the other attributes are null.
+func testProviderConfigure(t *testing.T, config map[string]string) *provider.ConfigureResponse {
+	t.Helper()
+	ctx := context.Background()
+	p := New()
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attrType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	for name, value := range config {
+		values[name] = tftypes.NewValue(tftypes.String, value)
+	}
+
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{
+		Config: tfsdk.Config{
+			Raw:    tftypes.NewValue(objectType, values),
+			Schema: schemaResp.Schema,
+		},
+	}, resp)
+	return resp
+}
+
+// testProviderCredentialConfig returns config with the required attributes
+// of provider added.
+func testProviderCredentialConfig(config map[string]string) map[string]string {
+	result := map[string]string{
+		"region":      "cn-bj2",
+		"zone":        "cn-bj2-02",
+		"project_id":  "org-test",
+		"public_key":  "test-public",
+		"private_key": "test-private",
+	}
+	for k, v := range config {
+		result[k] = v
+	}
+	return result
+}
+
+func testCheckAttributeError(t *testing.T, diags diag.Diagnostics, attrPath path.Path, summary string) {
+	t.Helper()
+	for _, d := range diags.Errors() {
+		if d, ok := d.(diag.DiagnosticWithPath); ok && d.Path().Equal(attrPath) && d.Summary() == summary {
+			return
+		}
+	}
+	t.Errorf("expected error %q of attribute %s, got %v", summary, attrPath, diags)
+}
+
+func testWriteFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testAccFakeServer starts a fake UCloud CDN API server that is closed when
+// the test finishes.
+func testAccFakeServer(t *testing.T) *fakeucdn.Server {
+	t.Helper()
+	s := fakeucdn.NewServer()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// testAccProviderConfig points the provider at the fake server.
+func testAccProviderConfig(s *fakeucdn.Server) string {
+	return fmt.Sprintf(`
+provider "st-ucloud" {
+  base_url    = %q
+  public_key  = %q
+  private_key = %q
+  region      = "cn-bj2"
+  zone        = "cn-bj2-02"
+  project_id  = "org-test"
+}
+`, s.URL, fakeucdn.PublicKey, fakeucdn.PrivateKey)
+}
+
+// testAccCertificate generates a self-signed certificate and its private
+// key in PEM format.
+func testAccCertificate(t *testing.T, commonName string, dnsNames ...string) (string, string) {
+	t.Helper()
+	c := newTestCertificate(t, commonName, dnsNames, nil, time.Now().Add(24*time.Hour))
+	return c.certPem, c.keyPem
+}
+
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPem string
+	keyPem  string
+}
+
+// newTestCertificate issues a certificate signed by parent, or a self-signed
+// CA certificate if parent is nil.
+func newTestCertificate(t *testing.T, commonName string, dnsNames []string, parent *testCertificate, notAfter time.Time) *testCertificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              append([]string{commonName}, dnsNames...),
+		NotBefore:             notAfter.Add(-48 * time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPem:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})),
+	}
+}
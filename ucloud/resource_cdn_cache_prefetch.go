@@ -0,0 +1,206 @@
+package ucloud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+)
+
+const (
+	defaultCdnCachePrefetchCreateTimeout = 20 * time.Minute
+)
+
+type cdnCachePrefetchResourceModel struct {
+	UrlList  types.List   `tfsdk:"url_list"`
+	Triggers types.Map    `tfsdk:"triggers"`
+	TaskIds  types.List   `tfsdk:"task_ids"`
+	Status   types.String `tfsdk:"status"`
+
+	ProjectId types.String `tfsdk:"project_id"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+type cdnCachePrefetchResource struct {
+	client *ucdn.UCDNClient
+}
+
+var (
+	_ resource.Resource              = &cdnCachePrefetchResource{}
+	_ resource.ResourceWithConfigure = &cdnCachePrefetchResource{}
+)
+
+func NewCdnCachePrefetchResource() resource.Resource {
+	return &cdnCachePrefetchResource{}
+}
+
+func (r *cdnCachePrefetchResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cdn_cache_prefetch"
+}
+
+func (r *cdnCachePrefetchResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "This resource submits prefetch tasks to warm up the edge caches of acceleration domains and waits until the tasks finish.",
+		Attributes: map[string]schema.Attribute{
+			"project_id": projectIdResourceAttribute(),
+			"url_list": &schema.ListAttribute{
+				Description: "The urls to prefetch.Each url must start with `http://` or `https://` followed by the acceleration domain.",
+				ElementType: types.StringType,
+				Required:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(cacheUrlRegexp, "must start with `http://` or `https://` followed by the acceleration domain"),
+					),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": &schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will submit the prefetch tasks again.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"task_ids": &schema.ListAttribute{
+				Description: "Id of prefetch tasks.Urls are submitted in batches of 100,one task for each batch.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"status": &schema.StringAttribute{
+				Description: "The final status of prefetch tasks.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+func (r *cdnCachePrefetchResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.client = req.ProviderData.(ucloudClients).cdnClient
+}
+
+func (r *cdnCachePrefetchResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model *cdnCachePrefetchResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var urlList []string
+	resp.Diagnostics.Append(model.UrlList.ElementsAs(ctx, &urlList, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectId := projectIdOf(r.client, model.ProjectId)
+	model.ProjectId = types.StringValue(projectId)
+
+	createTimeout, diags := model.Timeouts.Create(ctx, defaultCdnCachePrefetchCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	taskIds := make([]string, 0)
+	for _, batch := range splitUrlList(urlList, api.CacheTaskMaxUrlCount) {
+		taskId, err := api.PrefetchDomainCache(ctx, r.client, projectId, batch)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to Prefetch Cache", err.Error())
+			return
+		}
+		taskIds = append(taskIds, taskId)
+	}
+
+	for _, taskId := range taskIds {
+		task, err := api.WaitForPrefetchCacheTask(ctx, r.client, projectId, taskId)
+		if err == nil {
+			continue
+		}
+		if task == nil || task.Status != api.CacheTaskStatusFailure {
+			resp.Diagnostics.AddError("[API ERROR] Fail to Wait for Prefetch Task", err.Error())
+			continue
+		}
+		failedUrlCount := 0
+		for _, url := range task.UrlLists {
+			if url.Status == api.CacheTaskStatusSuccess {
+				continue
+			}
+			failedUrlCount++
+			resp.Diagnostics.AddAttributeError(
+				path.Root("url_list"),
+				"[API ERROR] Fail to Prefetch Url",
+				fmt.Sprintf("Url %s of prefetch task %s is %s.", url.Url, taskId, url.Status),
+			)
+		}
+		// The task may fail without reporting which url fails.
+		if failedUrlCount == 0 {
+			resp.Diagnostics.AddError("[API ERROR] Fail to Prefetch Cache", err.Error())
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.TaskIds, diags = types.ListValueFrom(ctx, types.StringType, taskIds)
+	resp.Diagnostics.Append(diags...)
+	model.Status = types.StringValue(api.CacheTaskStatusSuccess)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// Prefetch task is a one-off operation, there is nothing to read back.
+func (r *cdnCachePrefetchResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model *cdnCachePrefetchResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// All the configurable attributes require replacement, Update only keeps the computed fields.
+func (r *cdnCachePrefetchResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state *cdnCachePrefetchResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.TaskIds = state.TaskIds
+	plan.Status = state.Status
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Prefetched content expires with cache rules, Delete only removes the resource from state.
+func (r *cdnCachePrefetchResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+}
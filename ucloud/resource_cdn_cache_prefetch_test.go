@@ -0,0 +1,116 @@
+package ucloud
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+)
+
+func TestAccCdnCachePrefetchResource(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+resource "st-ucloud_cdn_cache_prefetch" "test" {
+  url_list = [
+    "http://test.example.com/a.js",
+    "http://test.example.com/b.js",
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_cache_prefetch.test", "url_list.#", "2"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_cache_prefetch.test", "task_ids.#", "1"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_cache_prefetch.test", "status", api.CacheTaskStatusSuccess),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCdnCachePrefetchResource_failure(t *testing.T) {
+	s := testAccFakeServer(t)
+	s.FailUrl("http://test.example.com/b.js")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+resource "st-ucloud_cdn_cache_prefetch" "test" {
+  url_list = [
+    "http://test.example.com/a.js",
+    "http://test.example.com/b.js",
+  ]
+}
+`,
+				ExpectError: regexp.MustCompile("Url http://test.example.com/b.js of prefetch task"),
+			},
+		},
+	})
+}
+
+func TestAccCdnCachePrefetchResource_taskFailure(t *testing.T) {
+	s := testAccFakeServer(t)
+	s.FailTask("http://test.example.com/a.js")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+resource "st-ucloud_cdn_cache_prefetch" "test" {
+  url_list = ["http://test.example.com/a.js"]
+}
+`,
+				ExpectError: regexp.MustCompile("prefetch task prefetch-[0-9]+ failed"),
+			},
+		},
+	})
+}
+
+func TestAccCdnCachePrefetchResource_timeout(t *testing.T) {
+	s := testAccFakeServer(t)
+	s.SetPendingPolls(1000)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+resource "st-ucloud_cdn_cache_prefetch" "test" {
+  url_list = ["http://test.example.com/index.html"]
+
+  timeouts {
+    create = "2s"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile("Fail to Wait for Prefetch Task"),
+			},
+		},
+	})
+}
+
+func TestAccCdnCachePrefetchResource_invalidUrl(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+resource "st-ucloud_cdn_cache_prefetch" "test" {
+  url_list = ["ftp://test.example.com/a.js"]
+}
+`,
+				ExpectError: regexp.MustCompile("must start with `http://` or `https://`"),
+			},
+		},
+	})
+}
@@ -0,0 +1,240 @@
+package ucloud
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+)
+
+// cacheUrlRegexp matches the urls of cache tasks, which are absolute http or
+// https urls.
+var cacheUrlRegexp = regexp.MustCompile(`^https?://[^/\s]+(/\S*)?$`)
+
+const (
+	defaultCdnCacheRefreshCreateTimeout = 20 * time.Minute
+)
+
+type cdnCacheRefreshResourceModel struct {
+	Type     types.String `tfsdk:"type"`
+	UrlList  types.List   `tfsdk:"url_list"`
+	Triggers types.Map    `tfsdk:"triggers"`
+	TaskIds  types.List   `tfsdk:"task_ids"`
+	Status   types.String `tfsdk:"status"`
+
+	ProjectId types.String `tfsdk:"project_id"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+type cdnCacheRefreshResource struct {
+	client *ucdn.UCDNClient
+}
+
+var (
+	_ resource.Resource                   = &cdnCacheRefreshResource{}
+	_ resource.ResourceWithConfigure      = &cdnCacheRefreshResource{}
+	_ resource.ResourceWithValidateConfig = &cdnCacheRefreshResource{}
+)
+
+func NewCdnCacheRefreshResource() resource.Resource {
+	return &cdnCacheRefreshResource{}
+}
+
+func (r *cdnCacheRefreshResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cdn_cache_refresh"
+}
+
+func (r *cdnCacheRefreshResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "This resource submits refresh tasks to purge cached content of acceleration domains and waits until the tasks succeed.",
+		Attributes: map[string]schema.Attribute{
+			"project_id": projectIdResourceAttribute(),
+			"type": &schema.StringAttribute{
+				Description: "The type of refresh.`file` refreshes the urls,`dir` refreshes all files under the directories.Default is `file`",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("file"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("file", "dir"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"url_list": &schema.ListAttribute{
+				Description: "The urls to refresh.Each url must start with `http://` or `https://` followed by the acceleration domain.Directories must end with `/`.",
+				ElementType: types.StringType,
+				Required:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(cacheUrlRegexp, "must start with `http://` or `https://` followed by the acceleration domain"),
+					),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": &schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will submit the refresh tasks again.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"task_ids": &schema.ListAttribute{
+				Description: "Id of refresh tasks.Urls are submitted in batches of 100,one task for each batch.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"status": &schema.StringAttribute{
+				Description: "The final status of refresh tasks.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+func (r *cdnCacheRefreshResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config cdnCacheRefreshResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Type.ValueString() != "dir" || config.UrlList.IsUnknown() {
+		return
+	}
+	for i, v := range config.UrlList.Elements() {
+		url, ok := v.(types.String)
+		if !ok || url.IsNull() || url.IsUnknown() {
+			continue
+		}
+		if !strings.HasSuffix(url.ValueString(), "/") {
+			resp.Diagnostics.AddAttributeError(path.Root("url_list").AtListIndex(i), "Invalid Directory Url",
+				fmt.Sprintf("Url %q must end with `/` to refresh a directory.", url.ValueString()))
+		}
+	}
+}
+
+func (r *cdnCacheRefreshResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.client = req.ProviderData.(ucloudClients).cdnClient
+}
+
+func (r *cdnCacheRefreshResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model *cdnCacheRefreshResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var urlList []string
+	resp.Diagnostics.Append(model.UrlList.ElementsAs(ctx, &urlList, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectId := projectIdOf(r.client, model.ProjectId)
+	model.ProjectId = types.StringValue(projectId)
+
+	createTimeout, diags := model.Timeouts.Create(ctx, defaultCdnCacheRefreshCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	taskIds := make([]string, 0)
+	for _, batch := range splitUrlList(urlList, api.CacheTaskMaxUrlCount) {
+		taskId, err := api.RefreshDomainCache(ctx, r.client, projectId, model.Type.ValueString(), batch)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to Refresh Cache", err.Error())
+			return
+		}
+		taskIds = append(taskIds, taskId)
+	}
+
+	for _, taskId := range taskIds {
+		_, err := api.WaitForRefreshCacheTask(ctx, r.client, projectId, taskId)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to Wait for Refresh Task", err.Error())
+			return
+		}
+	}
+
+	model.TaskIds, diags = types.ListValueFrom(ctx, types.StringType, taskIds)
+	resp.Diagnostics.Append(diags...)
+	model.Status = types.StringValue(api.CacheTaskStatusSuccess)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// Refresh task is a one-off operation, there is nothing to read back.
+func (r *cdnCacheRefreshResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model *cdnCacheRefreshResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, model)...)
+}
+
+// All the configurable attributes require replacement, Update only keeps the computed fields.
+func (r *cdnCacheRefreshResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state *cdnCacheRefreshResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.TaskIds = state.TaskIds
+	plan.Status = state.Status
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Purged cache can not be restored, Delete only removes the resource from state.
+func (r *cdnCacheRefreshResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+}
+
+func splitUrlList(urlList []string, size int) [][]string {
+	batches := make([][]string, 0)
+	for size < len(urlList) {
+		batches = append(batches, urlList[:size])
+		urlList = urlList[size:]
+	}
+	if len(urlList) > 0 {
+		batches = append(batches, urlList)
+	}
+	return batches
+}
@@ -0,0 +1,123 @@
+package ucloud
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+)
+
+func TestAccCdnCacheRefreshResource(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+resource "st-ucloud_cdn_cache_refresh" "test" {
+  type     = "dir"
+  url_list = ["http://test.example.com/static/"]
+  triggers = {
+    version = "1"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_cache_refresh.test", "type", "dir"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_cache_refresh.test", "task_ids.#", "1"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_cache_refresh.test", "status", api.CacheTaskStatusSuccess),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCdnCacheRefreshResource_failure(t *testing.T) {
+	s := testAccFakeServer(t)
+	s.FailUrl("http://test.example.com/index.html")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+resource "st-ucloud_cdn_cache_refresh" "test" {
+  url_list = ["http://test.example.com/index.html"]
+}
+`,
+				ExpectError: regexp.MustCompile("Refresh"),
+			},
+		},
+	})
+}
+
+func TestAccCdnCacheRefreshResource_timeout(t *testing.T) {
+	s := testAccFakeServer(t)
+	s.SetPendingPolls(1000)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+resource "st-ucloud_cdn_cache_refresh" "test" {
+  url_list = ["http://test.example.com/index.html"]
+
+  timeouts {
+    create = "2s"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile("Fail to Wait for Refresh Task"),
+			},
+		},
+	})
+}
+
+func TestAccCdnCacheRefreshResource_invalidUrl(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+resource "st-ucloud_cdn_cache_refresh" "test" {
+  url_list = ["test.example.com/index.html"]
+}
+`,
+				ExpectError: regexp.MustCompile("must start with `http://` or `https://`"),
+			},
+		},
+	})
+}
+
+func TestAccCdnCacheRefreshResource_invalidDirUrl(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + `
+resource "st-ucloud_cdn_cache_refresh" "test" {
+  type     = "dir"
+  url_list = ["http://test.example.com/static/", "http://test.example.com/assets"]
+}
+`,
+				ExpectError: regexp.MustCompile("Invalid Directory Url"),
+			},
+			{
+				// Urls of files don't need a trailing `/`.
+				Config: testAccProviderConfig(s) + `
+resource "st-ucloud_cdn_cache_refresh" "test" {
+  url_list = ["http://test.example.com/assets"]
+}
+`,
+				Check: resource.TestCheckResourceAttr("st-ucloud_cdn_cache_refresh.test", "status", api.CacheTaskStatusSuccess),
+			},
+		},
+	})
+}
@@ -2,13 +2,18 @@ package ucloud
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"reflect"
 	"strconv"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -21,12 +26,19 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
 	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
 	uerr "github.com/ucloud/ucloud-sdk-go/ucloud/error"
 	"github.com/ucloud/ucloud-sdk-go/ucloud/request"
 )
 
+const (
+	defaultCdnDomainCreateTimeout = 30 * time.Minute
+	defaultCdnDomainUpdateTimeout = 20 * time.Minute
+	defaultCdnDomainDeleteTimeout = 20 * time.Minute
+)
+
 type cacheRuleModel struct {
 	PathPattern      types.String `tfsdk:"path_pattern"`
 	Description      types.String `tfsdk:"description"`
@@ -48,17 +60,33 @@ type httpCodeCacheModel struct {
 	UseRegex         types.Bool   `tfsdk:"use_regex"`
 }
 
+type cacheKeyRuleModel struct {
+	PathPattern types.String `tfsdk:"path_pattern"`
+	QueryString types.String `tfsdk:"query_string"`
+	Ignore      types.Bool   `tfsdk:"ignore"`
+}
+
 type originConfigModel struct {
 	OriginIpList    types.List   `tfsdk:"origin_ip_list"`
 	OriginHost      types.String `tfsdk:"origin_host"`
 	OriginPort      types.Int64  `tfsdk:"origin_port"`
 	OriginProtocol  types.String `tfsdk:"origin_protocol"`
 	OriginFollow301 types.Bool   `tfsdk:"origin_follow301"`
+
+	OriginType types.String `tfsdk:"origin_type"`
+
+	BackupOrigin *backupOriginModel `tfsdk:"backup_origin"`
+}
+
+type backupOriginModel struct {
+	OriginIpList types.List   `tfsdk:"origin_ip_list"`
+	OriginHost   types.String `tfsdk:"origin_host"`
 }
 
 type cacheConfigModel struct {
 	RuleList             []*cacheRuleModel     `tfsdk:"cache_rule"`
 	HttpCodeCachRuleList []*httpCodeCacheModel `tfsdk:"http_code_cache_rule"`
+	CacheKeyRuleList     []*cacheKeyRuleModel  `tfsdk:"cache_key_rule"`
 }
 
 var referConfigAttributeTypes = map[string]attr.Type{
@@ -72,22 +100,36 @@ var accessControlConfigAttributeTypes = map[string]attr.Type{
 	"refer_conf":   types.ObjectType{}.WithAttributeTypes(referConfigAttributeTypes),
 }
 
+var headerRuleAttributeTypes = map[string]attr.Type{
+	"name":  types.StringType,
+	"value": types.StringType,
+}
+
 var advancedConfigAttributeTypes = map[string]attr.Type{
 	"http_client_header_list": types.ListType{}.WithElementType(types.StringType),
 	"http_origin_header_list": types.ListType{}.WithElementType(types.StringType),
+	"client_header":           types.ListType{}.WithElementType(types.ObjectType{}.WithAttributeTypes(headerRuleAttributeTypes)),
+	"origin_header":           types.ListType{}.WithElementType(types.ObjectType{}.WithAttributeTypes(headerRuleAttributeTypes)),
 	"http_to_https":           types.BoolType,
 }
 
+type headerRuleModel struct {
+	Name  types.String `tfsdk:"name"`
+	Value types.String `tfsdk:"value"`
+}
+
 type cdnDomainResourceModel struct {
 	DomainId   types.String `tfsdk:"domain_id"`
 	Domain     types.String `tfsdk:"domain"`
 	Cname      types.String `tfsdk:"cname"`
 	Status     types.String `tfsdk:"status"`
+	Enabled    types.Bool   `tfsdk:"enabled"`
 	CreateTime types.Int64  `tfsdk:"create_time"`
 	TestUrl    types.String `tfsdk:"test_url"`
 	AreaCode   types.String `tfsdk:"area_code"`
 	CdnType    types.String `tfsdk:"cdn_type"`
 	Tag        types.String `tfsdk:"tag"`
+	ProjectId  types.String `tfsdk:"project_id"`
 
 	OriginConfig *originConfigModel `tfsdk:"origin_conf"`
 
@@ -96,6 +138,8 @@ type cdnDomainResourceModel struct {
 	AccessControlConfig types.Object `tfsdk:"access_control_conf"`
 
 	AdvancedConf types.Object `tfsdk:"advanced_conf"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
 type cdnDomainResource struct {
@@ -103,9 +147,12 @@ type cdnDomainResource struct {
 }
 
 var (
-	_ resource.Resource               = &cdnDomainResource{}
-	_ resource.ResourceWithConfigure  = &cdnDomainResource{}
-	_ resource.ResourceWithModifyPlan = &cdnDomainResource{}
+	_ resource.Resource                   = &cdnDomainResource{}
+	_ resource.ResourceWithConfigure      = &cdnDomainResource{}
+	_ resource.ResourceWithModifyPlan     = &cdnDomainResource{}
+	_ resource.ResourceWithImportState    = &cdnDomainResource{}
+	_ resource.ResourceWithValidateConfig = &cdnDomainResource{}
+	_ resource.ResourceWithUpgradeState   = &cdnDomainResource{}
 )
 
 func NewCdnDomainResource() resource.Resource {
@@ -116,10 +163,31 @@ func (r *cdnDomainResource) Metadata(_ context.Context, req resource.MetadataReq
 	resp.TypeName = req.ProviderTypeName + "_cdn_domain"
 }
 
-func (r *cdnDomainResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func headerRuleAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Description: "The name of http header.",
+			Required:    true,
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(headerNameRegexp, "must be a valid http header name"),
+			},
+		},
+		"value": schema.StringAttribute{
+			Description: "The value of http header.",
+			Required:    true,
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(headerValueRegexp, "must not contain control characters"),
+			},
+		},
+	}
+}
+
+func (r *cdnDomainResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "This resource provides the configuration of acceleration domain",
+		Version:     1,
 		Attributes: map[string]schema.Attribute{
+			"project_id": projectIdResourceAttribute(),
 			"domain_id": &schema.StringAttribute{
 				Description: "Id of acceleration domain, generated by ucloud.",
 				Computed:    true,
@@ -136,6 +204,12 @@ func (r *cdnDomainResource) Schema(_ context.Context, req resource.SchemaRequest
 				Description: "Domain status",
 				Computed:    true,
 			},
+			"enabled": &schema.BoolAttribute{
+				Description: "Whether the domain is enabled.Disabling a domain takes it offline but keeps its configuration.Default is true.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
 			"create_time": &schema.Int64Attribute{
 				Description: "Create time.",
 				Computed:    true,
@@ -162,18 +236,38 @@ func (r *cdnDomainResource) Schema(_ context.Context, req resource.SchemaRequest
 				Description: "The advance configuration.",
 				Attributes: map[string]schema.Attribute{
 					"http_client_header_list": schema.ListAttribute{
-						Description: "Add http header when send response to client.",
-						ElementType: types.StringType,
-						Optional:    true,
-						Computed:    true,
-						Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+						Description:        "Add http header when send response to client.Conflicts with `client_header`.",
+						DeprecationMessage: "Use `client_header` instead.",
+						ElementType:        types.StringType,
+						Optional:           true,
+						Computed:           true,
+						Validators: []validator.List{
+							listvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("client_header")),
+						},
 					},
 					"http_origin_header_list": schema.ListAttribute{
-						Description: "Add http header when send request to origin",
-						ElementType: types.StringType,
-						Optional:    true,
-						Computed:    true,
-						Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+						Description:        "Add http header when send request to origin.Conflicts with `origin_header`.",
+						DeprecationMessage: "Use `origin_header` instead.",
+						ElementType:        types.StringType,
+						Optional:           true,
+						Computed:           true,
+						Validators: []validator.List{
+							listvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("origin_header")),
+						},
+					},
+					"client_header": schema.ListNestedAttribute{
+						Description: "The rules of http header in response to client.Each rule is sent to UCloud as `Name:Value` in `http_client_header_list`.Headers can only be added,as UCDN API has no option of setting or deleting headers.",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: headerRuleAttributes(),
+						},
+						Optional: true,
+					},
+					"origin_header": schema.ListNestedAttribute{
+						Description: "The rules of http header in request to origin.Each rule is sent to UCloud as `Name:Value` in `http_origin_header_list`.Headers can only be added,as UCDN API has no option of setting or deleting headers.",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: headerRuleAttributes(),
+						},
+						Optional: true,
 					},
 					"http_to_https": schema.BoolAttribute{
 						Description: "If perform a forced conversion from http to https.",
@@ -189,11 +283,14 @@ func (r *cdnDomainResource) Schema(_ context.Context, req resource.SchemaRequest
 				Description: "The configuration of access control.",
 				Attributes: map[string]schema.Attribute{
 					"ip_blacklist": schema.ListAttribute{
-						Description: "Request from address in blacklist will be denied.",
+						Description: "Request from address in blacklist will be denied.IPv4/IPv6 addresses and CIDRs are accepted.",
 						ElementType: types.StringType,
 						Optional:    true,
 						Computed:    true,
 						Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, []attr.Value{})),
+						Validators: []validator.List{
+							listvalidator.ValueStringsAre(ipOrCidrValidator{}),
+						},
 					},
 					"refer_conf": &schema.SingleNestedAttribute{
 						Description: "",
@@ -230,13 +327,21 @@ func (r *cdnDomainResource) Schema(_ context.Context, req resource.SchemaRequest
 			},
 		},
 		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
 			"origin_conf": &schema.SingleNestedBlock{
 				Description: "The configuration of origin",
 				Attributes: map[string]schema.Attribute{
 					"origin_ip_list": schema.ListAttribute{
-						Description: "The ip list of origin",
+						Description: "The ip list of origin.Hostnames are accepted as well, e.g. the domain of a US3 bucket, and are sent to UCloud in the same `OriginIp` parameter as ips.IPs and hostnames can't be mixed.Only public US3 buckets are supported,as UCDN API has no option of private bucket authentication.",
 						ElementType: types.StringType,
 						Required:    true,
+						Validators: []validator.List{
+							listvalidator.ValueStringsAre(originAddressValidator{}),
+						},
 					},
 					"origin_host": schema.StringAttribute{
 						Description: "The host of origin",
@@ -264,10 +369,35 @@ func (r *cdnDomainResource) Schema(_ context.Context, req resource.SchemaRequest
 						Computed:    true,
 						Default:     booldefault.StaticBool(false),
 					},
+					"origin_type": schema.StringAttribute{
+						Description: "The type of origin inferred from `origin_ip_list`.`ip` means it contains ip addresses,`domain` means it contains hostnames,`us3` means it contains domains of US3 buckets like `bucket.cn-bj.ufileos.com`,whose `origin_host` defaults to the bucket domain.",
+						Computed:    true,
+					},
+				},
+				Blocks: map[string]schema.Block{
+					"backup_origin": schema.SingleNestedBlock{
+						Description: "The configuration of backup origin.Requests are sent to backup origin when origin fails.If the block is unset,backup origin is disabled.",
+						Attributes: map[string]schema.Attribute{
+							"origin_ip_list": schema.ListAttribute{
+								Description: "The ip list of backup origin.It must be set when `backup_origin` is set.",
+								ElementType: types.StringType,
+								Optional:    true,
+								Validators: []validator.List{
+									listvalidator.SizeAtLeast(1),
+									listvalidator.ValueStringsAre(originAddressValidator{}),
+								},
+							},
+							"origin_host": schema.StringAttribute{
+								Description: "The host of backup origin.If the value is unset,`origin_host` of origin is used.",
+								Optional:    true,
+								Computed:    true,
+							},
+						},
+					},
 				},
 			},
 			"cache_conf": schema.SingleNestedBlock{
-				Description: "The configuration of cache",
+				Description: "The configuration of cache.If the block is unset,cache rules of the domain are not managed and no default cache rule of path `/` is created.",
 				Blocks: map[string]schema.Block{
 					"cache_rule": &schema.ListNestedBlock{
 						Description: "The list of cache rule",
@@ -373,6 +503,29 @@ func (r *cdnDomainResource) Schema(_ context.Context, req resource.SchemaRequest
 							},
 						},
 					},
+					"cache_key_rule": &schema.ListNestedBlock{
+						Description: "The list of rule on query string of cache key",
+						NestedObject: schema.NestedBlockObject{
+							Attributes: map[string]schema.Attribute{
+								"path_pattern": schema.StringAttribute{
+									Description: "The pattern of path,regex is supported",
+									Required:    true,
+								},
+								"query_string": schema.StringAttribute{
+									Description: "The query string parameters,each starts with `$` and they are joined with `+`,e.g. `$a+$b`.`$querystring` represents all parameters.Default is `$querystring`",
+									Optional:    true,
+									Computed:    true,
+									Default:     stringdefault.StaticString("$querystring"),
+								},
+								"ignore": schema.BoolAttribute{
+									Description: "If the parameters in `query_string` are ignored when caching.Default is true",
+									Optional:    true,
+									Computed:    true,
+									Default:     booldefault.StaticBool(true),
+								},
+							},
+						},
+					},
 				},
 			},
 		},
@@ -396,6 +549,16 @@ func (r *cdnDomainResource) Create(ctx context.Context, req resource.CreateReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	model.ProjectId = types.StringValue(projectIdOf(r.client, model.ProjectId))
+
+	createTimeout, diags := model.Timeouts.Create(ctx, defaultCdnDomainCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	createCdnDomainRequest, diags := r.buildCreateCdnDomainRequest(model)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
@@ -426,30 +589,51 @@ func (r *cdnDomainResource) Create(ctx context.Context, req resource.CreateReque
 	}
 	reconnectBackoff := backoff.NewExponentialBackOff()
 	reconnectBackoff.MaxElapsedTime = 30 * time.Second
-	err = backoff.Retry(createCdnDomain, reconnectBackoff)
+	err = backoff.Retry(createCdnDomain, backoff.WithContext(reconnectBackoff, ctx))
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Create CdnDomain", err.Error())
 		return
 	}
 	model.DomainId = types.StringValue(createCdnDomainResponse.DomainList[0].DomainId)
-	status, err := api.WaitForDomainStatus(r.client, model.DomainId.ValueString(), []string{api.DomainStatusEnable, api.DomainStatusCheckFail})
+	// Save the domain to state right away, so that it is tainted rather than
+	// orphaned if any of the steps below fails.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), model.ProjectId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain_id"), model.DomainId)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	status, err := api.WaitForDomainStatus(ctx, r.client, model.ProjectId.ValueString(), model.DomainId.ValueString(), []string{api.DomainStatusEnable, api.DomainStatusCheckFail})
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Get CdnDomain Status", err.Error())
 		return
 	}
 
 	if status == api.DomainStatusCheckFail {
-		api.DeleteDomain(r.client, model.DomainId.ValueString())
 		resp.Diagnostics.AddError("[API ERROR] Fail to Create CdnDomain", "Domain audit failed")
+		err = api.DeleteDomain(ctx, r.client, model.ProjectId.ValueString(), model.DomainId.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to Delete CdnDomain", err.Error())
+			return
+		}
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
-	err = api.UpdateCdnDomain(r.client, r.buildUpdateCdnDomainRequest(model))
+	err = api.UpdateCdnDomain(ctx, r.client, r.buildUpdateCdnDomainRequest(model))
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Update CdnDomain", err.Error())
+		return
 	}
 
-	domainConfig, err := api.GetUcdnDomainConfig(r.client, model.DomainId.ValueString())
+	if !model.Enabled.ValueBool() {
+		_, err = api.UpdateDomainStatus(ctx, r.client, model.ProjectId.ValueString(), model.DomainId.ValueString(), api.DomainStatusDisable)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to Disable CdnDomain", err.Error())
+		}
+	}
+
+	domainConfig, err := api.GetUcdnDomainConfig(ctx, r.client, model.ProjectId.ValueString(), model.DomainId.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Get CdnDomain", err.Error())
 		return
@@ -469,8 +653,9 @@ func (r *cdnDomainResource) Read(ctx context.Context, req resource.ReadRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	model.ProjectId = types.StringValue(projectIdOf(r.client, model.ProjectId))
 
-	domainConfig, err := api.GetUcdnDomainConfig(r.client, model.DomainId.ValueString())
+	domainConfig, err := api.GetUcdnDomainConfig(ctx, r.client, model.ProjectId.ValueString(), model.DomainId.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Read CdnDomain", err.Error())
 		return
@@ -503,14 +688,40 @@ func (r *cdnDomainResource) Update(ctx context.Context, req resource.UpdateReque
 		return
 	}
 	model.DomainId = state.DomainId
+	model.ProjectId = types.StringValue(projectIdOf(r.client, model.ProjectId))
 
-	err := api.UpdateCdnDomain(r.client, r.buildUpdateCdnDomainRequest(model))
+	updateTimeout, diags := model.Timeouts.Update(ctx, defaultCdnDomainUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// The config is updated before the status is switched in either
+	// direction, so that a failed update leaves the status untouched.
+	copyUcloudCdnDomainResourceModelComputeFields(model, state)
+	err := api.UpdateCdnDomain(ctx, r.client, r.buildUpdateCdnDomainRequest(model))
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Update CdnDomain", err.Error())
+		return
 	}
 
-	copyUcloudCdnDomainResourceModelComputeFields(model, state)
-	model.Status = types.StringValue(api.DomainStatusEnable)
+	if model.Enabled.ValueBool() && !state.Enabled.ValueBool() {
+		status, err := api.UpdateDomainStatus(ctx, r.client, model.ProjectId.ValueString(), model.DomainId.ValueString(), api.DomainStatusEnable)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to Enable CdnDomain", err.Error())
+			return
+		}
+		model.Status = types.StringValue(status)
+	} else if !model.Enabled.ValueBool() && state.Enabled.ValueBool() {
+		status, err := api.UpdateDomainStatus(ctx, r.client, model.ProjectId.ValueString(), model.DomainId.ValueString(), api.DomainStatusDisable)
+		if err != nil {
+			resp.Diagnostics.AddError("[API ERROR] Fail to Disable CdnDomain", err.Error())
+			return
+		}
+		model.Status = types.StringValue(status)
+	}
 
 	resp.State.Set(ctx, &model)
 }
@@ -523,12 +734,59 @@ func (r *cdnDomainResource) Delete(ctx context.Context, req resource.DeleteReque
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	model.ProjectId = types.StringValue(projectIdOf(r.client, model.ProjectId))
 
-	api.DeleteDomain(r.client, model.DomainId.ValueString())
+	deleteTimeout, diags := model.Timeouts.Delete(ctx, defaultCdnDomainDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := api.DeleteDomain(ctx, r.client, model.ProjectId.ValueString(), model.DomainId.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to Delete CdnDomain", err.Error())
+	}
 }
 
 func (r *cdnDomainResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("domain_id"), req, resp)
+	projectId, id := parseImportId(r.client, req.ID)
+	domainConfig, err := getUcdnDomainConfigByIdOrName(ctx, r.client, projectId, id)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to Get CdnDomain", err.Error())
+		return
+	}
+	if domainConfig == nil {
+		resp.Diagnostics.AddError("Domain Not Found", fmt.Sprintf("Domain %s is not found.", req.ID))
+		return
+	}
+
+	// Nested blocks are populated by the Read following import.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), projectId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain_id"), domainConfig.DomainId)...)
+}
+
+func (r *cdnDomainResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {StateUpgrader: upgradeCdnDomainStateV0},
+	}
+}
+
+func (r *cdnDomainResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config *cdnDomainResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.OriginConfig == nil {
+		return
+	}
+	resp.Diagnostics.Append(validateOriginConfig(ctx, config.OriginConfig)...)
+
+	// Attributes of a nested block can't be required, otherwise they are
+	// required even if the block is unset.
+	if config.OriginConfig.BackupOrigin != nil && config.OriginConfig.BackupOrigin.OriginIpList.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("origin_conf").AtName("backup_origin").AtName("origin_ip_list"), "Missing Configuration for Required Attribute",
+			"Must set a configuration value for `origin_ip_list` when `backup_origin` is set.")
+	}
 }
 
 func (r *cdnDomainResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
@@ -543,34 +801,53 @@ func (r *cdnDomainResource) ModifyPlan(ctx context.Context, req resource.ModifyP
 	}
 
 	if plan.OriginConfig != nil {
+		var originIpList []string
+		if !plan.OriginConfig.OriginIpList.IsUnknown() && plan.OriginConfig.OriginIpList.ElementsAs(ctx, &originIpList, false) == nil {
+			plan.OriginConfig.OriginType = types.StringValue(originTypeOf(originIpList))
+		}
 		if plan.OriginConfig.OriginHost.IsNull() || plan.OriginConfig.OriginHost.IsUnknown() {
-			plan.OriginConfig.OriginHost = plan.Domain
+			// US3 requires the host of request to be the bucket domain.
+			if plan.OriginConfig.OriginType.ValueString() == originTypeUs3 && len(originIpList) > 0 {
+				plan.OriginConfig.OriginHost = types.StringValue(originIpList[0])
+			} else {
+				plan.OriginConfig.OriginHost = plan.Domain
+			}
 		}
-	}
-
-	if plan.CacheConf == nil {
-		plan.CacheConf = &cacheConfigModel{}
-	}
-	if plan.CacheConf.RuleList == nil || len(plan.CacheConf.RuleList) == 0 {
-		rule := &cacheRuleModel{
-			PathPattern:      types.StringValue("/"),
-			TTL:              types.Int64Value(0),
-			CacheUnit:        types.StringValue("sec"),
-			Description:      types.StringValue(""),
-			CacheBehavior:    types.BoolValue(true),
-			FollowOriginRule: types.BoolValue(false),
-			UseRegex:         types.BoolValue(false),
+		if plan.OriginConfig.BackupOrigin != nil {
+			if plan.OriginConfig.BackupOrigin.OriginHost.IsNull() || plan.OriginConfig.BackupOrigin.OriginHost.IsUnknown() {
+				plan.OriginConfig.BackupOrigin.OriginHost = plan.OriginConfig.OriginHost
+			}
 		}
-		plan.CacheConf.RuleList = []*cacheRuleModel{rule}
 	}
-	resp.Plan.SetAttribute(ctx, path.Root("cache_conf"), plan.CacheConf)
 
+	var configAdvancedConf types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("advanced_conf"), &configAdvancedConf)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	if plan.AdvancedConf.IsNull() || plan.AdvancedConf.IsUnknown() {
 		plan.AdvancedConf = types.ObjectValueMust(advancedConfigAttributeTypes, map[string]attr.Value{
 			"http_client_header_list": types.ListValueMust(types.StringType, []attr.Value{}),
 			"http_origin_header_list": types.ListValueMust(types.StringType, []attr.Value{}),
+			"client_header":           types.ListNull(types.ObjectType{AttrTypes: headerRuleAttributeTypes}),
+			"origin_header":           types.ListNull(types.ObjectType{AttrTypes: headerRuleAttributeTypes}),
 			"http_to_https":           types.BoolValue(false),
 		})
+	} else if !configAdvancedConf.IsNull() && !configAdvancedConf.IsUnknown() {
+		// The header list in API format is derived from the header rules if
+		// they are set.
+		attrs := plan.AdvancedConf.Attributes()
+		configAttrs := configAdvancedConf.Attributes()
+		clientHeaderList, diags := planHeaderList(ctx, configAttrs["http_client_header_list"].(types.List), attrs["client_header"].(types.List))
+		resp.Diagnostics.Append(diags...)
+		originHeaderList, diags := planHeaderList(ctx, configAttrs["http_origin_header_list"].(types.List), attrs["origin_header"].(types.List))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		attrs["http_client_header_list"] = clientHeaderList
+		attrs["http_origin_header_list"] = originHeaderList
+		plan.AdvancedConf = types.ObjectValueMust(advancedConfigAttributeTypes, attrs)
 	}
 
 	if plan.AccessControlConfig.IsNull() || plan.AccessControlConfig.IsUnknown() || plan.AccessControlConfig.Attributes()["refer_conf"].IsNull() || plan.AccessControlConfig.Attributes()["refer_conf"].IsUnknown() {
@@ -617,11 +894,11 @@ func (r *cdnDomainResource) buildCreateCdnDomainRequest(m *cdnDomainResourceMode
 	}
 	domainConfig.AreaCode = m.AreaCode.ValueStringPointer()
 	domainConfig.CdnType = m.CdnType.ValueStringPointer()
-	domainConfig.Tag = m.TestUrl.ValueStringPointer()
+	domainConfig.Tag = m.Tag.ValueStringPointer()
 
 	return &api.CreateCdnDomainRequest{
 		CommonBase: request.CommonBase{
-			ProjectId: &r.client.GetConfig().ProjectId,
+			ProjectId: m.ProjectId.ValueStringPointer(),
 		},
 		DomainList: []api.CreateDomainConfig{domainConfig},
 	}, diags
@@ -641,6 +918,17 @@ func (r *cdnDomainResource) buildUpdateCdnDomainRequest(m *cdnDomainResourceMode
 			val = 1
 		}
 		domainConf.OriginConf.OriginFollow301 = &val
+		// Origin type can't be inferred during plan if origin_ip_list is
+		// unknown, so it is resolved here before being saved to state.
+		if m.OriginConfig.OriginType.IsUnknown() {
+			m.OriginConfig.OriginType = types.StringValue(originTypeOf(domainConf.OriginConf.OriginIp))
+		}
+		backupOriginEnable := m.OriginConfig.BackupOrigin != nil
+		domainConf.OriginConf.BackupOriginEnable = &backupOriginEnable
+		if backupOriginEnable {
+			m.OriginConfig.BackupOrigin.OriginIpList.ElementsAs(nil, &domainConf.OriginConf.BackupOriginIp, false)
+			domainConf.OriginConf.BackupOriginHost = m.OriginConfig.BackupOrigin.OriginHost.ValueStringPointer()
+		}
 	}
 	// cache control
 	if m.CacheConf != nil {
@@ -671,6 +959,14 @@ func (r *cdnDomainResource) buildUpdateCdnDomainRequest(m *cdnDomainResourceMode
 			}
 			domainConf.CacheConf.HttpCodeCacheList = append(domainConf.CacheConf.HttpCodeCacheList, rule)
 		}
+		domainConf.CacheConf.CacheKeyList = make([]ucdn.CacheKeyInfo, 0)
+		for _, rule := range m.CacheConf.CacheKeyRuleList {
+			domainConf.CacheConf.CacheKeyList = append(domainConf.CacheConf.CacheKeyList, ucdn.CacheKeyInfo{
+				PathPattern: rule.PathPattern.ValueString(),
+				QueryString: rule.QueryString.ValueString(),
+				Ignore:      rule.Ignore.ValueBool(),
+			})
+		}
 	}
 	// access control
 	if !m.AccessControlConfig.IsNull() {
@@ -724,7 +1020,7 @@ func (r *cdnDomainResource) buildUpdateCdnDomainRequest(m *cdnDomainResourceMode
 
 	return &api.UpdateCdnDomainRequest{
 		CommonBase: request.CommonBase{
-			ProjectId: &r.client.GetConfig().ProjectId,
+			ProjectId: m.ProjectId.ValueStringPointer(),
 		},
 		DomainList: []api.UpdateCdnDomainConfig{domainConf},
 	}
@@ -755,9 +1051,16 @@ func updateUcloudCdnDomainResourceModel(ctx context.Context, model *cdnDomainRes
 		return result
 	}
 
+	// Nothing but the id is known about an imported domain.
+	imported := model.Domain.IsNull()
+
+	model.DomainId = types.StringValue(info.DomainId)
+	model.Domain = types.StringValue(info.Domain)
+	model.Tag = types.StringValue(info.Tag)
 	model.AreaCode = types.StringValue(info.AreaCode)
 	model.CdnType = types.StringValue(info.CdnType)
 	model.Status = types.StringValue(info.Status)
+	model.Enabled = types.BoolValue(info.Status != api.DomainStatusDisable)
 	model.Cname = types.StringValue(info.Cname)
 	model.CreateTime = types.Int64Value(int64(info.CreateTime))
 	model.TestUrl = types.StringValue(info.TestUrl)
@@ -776,39 +1079,59 @@ func updateUcloudCdnDomainResourceModel(ctx context.Context, model *cdnDomainRes
 	} else {
 		model.OriginConfig.OriginFollow301 = types.BoolValue(false)
 	}
+	model.OriginConfig.OriginType = types.StringValue(originTypeOf(info.OriginConf.OriginIpList))
+	if info.OriginConf.BackupOriginEnable {
+		model.OriginConfig.BackupOrigin = &backupOriginModel{}
+		model.OriginConfig.BackupOrigin.OriginIpList, diags = types.ListValueFrom(ctx, types.StringType, info.OriginConf.BackupOriginIpList)
+		result.Append(diags...)
+		model.OriginConfig.BackupOrigin.OriginHost = types.StringValue(info.OriginConf.BackupOriginHost)
+	}
 
-	model.CacheConf = &cacheConfigModel{}
-	model.CacheConf.RuleList = make([]*cacheRuleModel, 0)
-	model.CacheConf.HttpCodeCachRuleList = make([]*httpCodeCacheModel, 0)
-	for _, rule := range info.CacheConf.CacheList {
-		c := &cacheRuleModel{
-			PathPattern:      types.StringValue(rule.PathPattern),
-			Description:      types.StringValue(rule.Description),
-			TTL:              types.Int64Value(int64(rule.CacheTTL)),
-			CacheUnit:        types.StringValue(rule.CacheUnit),
-			CacheBehavior:    types.BoolValue(rule.CacheBehavior),
-			FollowOriginRule: types.BoolValue(rule.FollowOriginRule),
-			UseRegex:         types.BoolValue(rule.UseRegex),
-		}
-		model.CacheConf.RuleList = append(model.CacheConf.RuleList, c)
-	}
-	for _, rule := range info.CacheConf.HttpCodeCacheList {
-		c := &httpCodeCacheModel{
-			PathPattern:      types.StringValue(rule.PathPattern),
-			Description:      types.StringValue(rule.Description),
-			TTL:              types.Int64Value(int64(rule.CacheTTL)),
-			CacheUnit:        types.StringValue(rule.CacheUnit),
-			CacheBehavior:    types.BoolValue(rule.CacheBehavior),
-			FollowOriginRule: types.BoolValue(rule.FollowOriginRule),
-			UseRegex:         types.BoolValue(rule.UseRegex),
-		}
-		code, err := strconv.Atoi(rule.HttpCodePattern)
-		if err != nil {
-			result.AddError("[Invalid Api Response]", err.Error())
-			return result
+	// The cache_conf block is left unmanaged if it is unset, as Terraform
+	// rejects blocks that are absent in config but present in state.
+	hasCacheRules := len(info.CacheConf.CacheList) > 0 || len(info.CacheConf.HttpCodeCacheList) > 0 || len(info.CacheConf.CacheKeyList) > 0
+	if model.CacheConf != nil || (imported && hasCacheRules) {
+		model.CacheConf = &cacheConfigModel{}
+		model.CacheConf.RuleList = make([]*cacheRuleModel, 0)
+		model.CacheConf.HttpCodeCachRuleList = make([]*httpCodeCacheModel, 0)
+		model.CacheConf.CacheKeyRuleList = make([]*cacheKeyRuleModel, 0)
+		for _, rule := range info.CacheConf.CacheList {
+			c := &cacheRuleModel{
+				PathPattern:      types.StringValue(rule.PathPattern),
+				Description:      types.StringValue(rule.Description),
+				TTL:              types.Int64Value(int64(rule.CacheTTL)),
+				CacheUnit:        types.StringValue(rule.CacheUnit),
+				CacheBehavior:    types.BoolValue(rule.CacheBehavior),
+				FollowOriginRule: types.BoolValue(rule.FollowOriginRule),
+				UseRegex:         types.BoolValue(rule.UseRegex),
+			}
+			model.CacheConf.RuleList = append(model.CacheConf.RuleList, c)
+		}
+		for _, rule := range info.CacheConf.HttpCodeCacheList {
+			c := &httpCodeCacheModel{
+				PathPattern:      types.StringValue(rule.PathPattern),
+				Description:      types.StringValue(rule.Description),
+				TTL:              types.Int64Value(int64(rule.CacheTTL)),
+				CacheUnit:        types.StringValue(rule.CacheUnit),
+				CacheBehavior:    types.BoolValue(rule.CacheBehavior),
+				FollowOriginRule: types.BoolValue(rule.FollowOriginRule),
+				UseRegex:         types.BoolValue(rule.UseRegex),
+			}
+			code, err := strconv.Atoi(rule.HttpCodePattern)
+			if err != nil {
+				result.AddError("[Invalid Api Response]", err.Error())
+				return result
+			}
+			c.HttpCode = types.Int64Value(int64(code))
+			model.CacheConf.HttpCodeCachRuleList = append(model.CacheConf.HttpCodeCachRuleList, c)
+		}
+		for _, rule := range info.CacheConf.CacheKeyList {
+			model.CacheConf.CacheKeyRuleList = append(model.CacheConf.CacheKeyRuleList, &cacheKeyRuleModel{
+				PathPattern: types.StringValue(rule.PathPattern),
+				QueryString: types.StringValue(rule.QueryString),
+				Ignore:      types.BoolValue(rule.Ignore),
+			})
 		}
-		c.HttpCode = types.Int64Value(int64(code))
-		model.CacheConf.HttpCodeCachRuleList = append(model.CacheConf.HttpCodeCachRuleList, c)
 	}
 
 	referList, diags := types.ListValueFrom(ctx, types.StringType, info.AccessControlConf.ReferConf.ReferList)
@@ -849,11 +1172,188 @@ func updateUcloudCdnDomainResourceModel(ctx context.Context, model *cdnDomainRes
 	if originHeaderList.IsNull() {
 		originHeaderList = types.ListValueMust(types.StringType, []attr.Value{})
 	}
+	// Header rules are only read if they are managed. Headers of an imported
+	// domain are imported into header rules as well, so that the legacy lists
+	// match the lists derived from the rules in plan. They are only kept in
+	// the legacy lists if some of them can't be parsed.
+	clientHeader := types.ListNull(types.ObjectType{AttrTypes: headerRuleAttributeTypes})
+	originHeader := types.ListNull(types.ObjectType{AttrTypes: headerRuleAttributeTypes})
+	if imported {
+		clientHeader, diags = importHeaderRuleList(path.Root("advanced_conf").AtName("http_client_header_list"), info.AdvancedConf.HttpClientHeader)
+		result.Append(diags...)
+		originHeader, diags = importHeaderRuleList(path.Root("advanced_conf").AtName("http_origin_header_list"), info.AdvancedConf.HttpOriginHeader)
+		result.Append(diags...)
+	} else {
+		if !model.AdvancedConf.IsNull() && !model.AdvancedConf.Attributes()["client_header"].IsNull() {
+			clientHeader, diags = headerRuleListOf(path.Root("advanced_conf").AtName("client_header"), info.AdvancedConf.HttpClientHeader)
+			result.Append(diags...)
+		}
+		if !model.AdvancedConf.IsNull() && !model.AdvancedConf.Attributes()["origin_header"].IsNull() {
+			originHeader, diags = headerRuleListOf(path.Root("advanced_conf").AtName("origin_header"), info.AdvancedConf.HttpOriginHeader)
+			result.Append(diags...)
+		}
+	}
 	model.AdvancedConf = types.ObjectValueMust(advancedConfigAttributeTypes, map[string]attr.Value{
 		"http_client_header_list": clientHeaderList,
 		"http_origin_header_list": originHeaderList,
+		"client_header":           clientHeader,
+		"origin_header":           originHeader,
 		"http_to_https":           types.BoolValue(info.AdvancedConf.Http2Https),
 	})
 
 	return result
 }
+
+// validateOriginConfig checks that addresses of origin are either all IPs or
+// all hostnames.
+func validateOriginConfig(ctx context.Context, origin *originConfigModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if origin.OriginIpList.IsUnknown() {
+		return diags
+	}
+	var addrs []types.String
+	diags.Append(origin.OriginIpList.ElementsAs(ctx, &addrs, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	ipCount := 0
+	for _, addr := range addrs {
+		if addr.IsUnknown() {
+			return diags
+		}
+		if net.ParseIP(addr.ValueString()) != nil {
+			ipCount++
+		}
+	}
+	if ipCount > 0 && ipCount < len(addrs) {
+		diags.AddAttributeError(path.Root("origin_conf").AtName("origin_ip_list"), "Mixed Origin Addresses",
+			"IP addresses and hostnames can't be mixed in `origin_ip_list`.")
+	}
+	return diags
+}
+
+// planHeaderList derives the header list in API format of plan from the
+// header rules if they are set, otherwise the header list in config is used.
+func planHeaderList(ctx context.Context, configHeaderList, planRules types.List) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if planRules.IsNull() {
+		if configHeaderList.IsNull() {
+			return types.ListValueMust(types.StringType, []attr.Value{}), diags
+		}
+		return configHeaderList, diags
+	}
+	if planRules.IsUnknown() {
+		return types.ListUnknown(types.StringType), diags
+	}
+
+	var rules []*headerRuleModel
+	diags.Append(planRules.ElementsAs(ctx, &rules, true)...)
+	if diags.HasError() {
+		return configHeaderList, diags
+	}
+	headerList := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if rule == nil || rule.Name.IsUnknown() || rule.Value.IsUnknown() {
+			return types.ListUnknown(types.StringType), diags
+		}
+		headerList = append(headerList, formatHeaderRule(headerRule{
+			Name:  rule.Name.ValueString(),
+			Value: rule.Value.ValueString(),
+		}))
+	}
+	headerListValue, d := types.ListValueFrom(ctx, types.StringType, headerList)
+	diags.Append(d...)
+	return headerListValue, diags
+}
+
+// headerRulesOf parses headers in API format into header rules. Headers in
+// unknown format are left out and passed to invalid.
+func headerRulesOf(headers []string, invalid func(header string, err error)) []attr.Value {
+	rules := make([]attr.Value, 0, len(headers))
+	for _, header := range headers {
+		rule, err := parseHeaderRule(header)
+		if err != nil {
+			invalid(header, err)
+			continue
+		}
+		rules = append(rules, types.ObjectValueMust(headerRuleAttributeTypes, map[string]attr.Value{
+			"name":  types.StringValue(rule.Name),
+			"value": types.StringValue(rule.Value),
+		}))
+	}
+	return rules
+}
+
+// headerRuleListOf parses headers in API format into header rules. Headers in
+// unknown format are left out with a warning on attrPath.
+func headerRuleListOf(attrPath path.Path, headers []string) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	rules := headerRulesOf(headers, func(header string, err error) {
+		diags.AddAttributeWarning(attrPath, "Invalid Header",
+			fmt.Sprintf("Header %q of the domain is left out as it can't be parsed: %s.", header, err))
+	})
+	return types.ListValueMust(types.ObjectType{AttrTypes: headerRuleAttributeTypes}, rules), diags
+}
+
+// importHeaderRuleList parses headers of an imported domain into header
+// rules. As a list of header rules can't hold a header in unknown format, the
+// rules are left null if any header can't be parsed, and all the headers are
+// kept as is in the legacy list on attrPath with a warning for each of them.
+func importHeaderRuleList(attrPath path.Path, headers []string) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if len(headers) == 0 {
+		return types.ListNull(types.ObjectType{AttrTypes: headerRuleAttributeTypes}), diags
+	}
+	rules := headerRulesOf(headers, func(header string, err error) {
+		diags.AddAttributeWarning(attrPath, "Invalid Header",
+			fmt.Sprintf("Header %q of the domain can't be parsed: %s. Headers are imported as is instead of as header rules.", header, err))
+	})
+	if diags.WarningsCount() > 0 {
+		return types.ListNull(types.ObjectType{AttrTypes: headerRuleAttributeTypes}), diags
+	}
+	return types.ListValueMust(types.ObjectType{AttrTypes: headerRuleAttributeTypes}, rules), diags
+}
+
+// upgradeCdnDomainStateV0 drops the default cache rule of path `/` that
+// version 0 planned if cache_conf was unset. As cache_conf is left unmanaged
+// if it is unset, keeping the rule in state would plan to remove the block,
+// while the rule is kept on the domain anyway.
+func upgradeCdnDomainStateV0(_ context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var state map[string]interface{}
+	if err := json.Unmarshal(req.RawState.JSON, &state); err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade Resource State", err.Error())
+		return
+	}
+	if isDefaultCacheConfV0(state["cache_conf"]) {
+		state["cache_conf"] = nil
+	}
+	upgraded, err := json.Marshal(state)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Upgrade Resource State", err.Error())
+		return
+	}
+	resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+}
+
+// isDefaultCacheConfV0 reports whether cacheConf in state of version 0 only
+// has the default cache rule of path `/`.
+func isDefaultCacheConfV0(cacheConf interface{}) bool {
+	conf, ok := cacheConf.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if rules, _ := conf["http_code_cache_rule"].([]interface{}); len(rules) > 0 {
+		return false
+	}
+	rules, _ := conf["cache_rule"].([]interface{})
+	return len(rules) == 1 && reflect.DeepEqual(rules[0], map[string]interface{}{
+		"path_pattern":       "/",
+		"description":        "",
+		"ttl":                float64(0),
+		"cache_unit":         "sec",
+		"cache_behavior":     true,
+		"follow_origin_rule": false,
+		"use_regex":          false,
+	})
+}
@@ -2,19 +2,69 @@ package ucloud
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
 	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
 )
 
+const (
+	defaultCdnDomainSslAssociationCreateTimeout = 20 * time.Minute
+	defaultCdnDomainSslAssociationUpdateTimeout = 20 * time.Minute
+	defaultCdnDomainSslAssociationDeleteTimeout = 20 * time.Minute
+)
+
 type cdnDomainSslAssociationModel struct {
+	ProjectId          types.String `tfsdk:"project_id"`
 	DomainId           types.String `tfsdk:"domain_id"`
 	SslCertificateName types.String `tfsdk:"ssl_certificate_name"`
+	CertNameCn         types.String `tfsdk:"cert_name_cn"`
+	CertNameAbroad     types.String `tfsdk:"cert_name_abroad"`
+	SkipSanCheck       types.Bool   `tfsdk:"skip_san_check"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// certNames returns the certificate name of each area that HTTPS should be
+// enabled for a domain accelerated in areaCode.
+func (m *cdnDomainSslAssociationModel) certNames(areaCode string) map[string]string {
+	certNames := make(map[string]string)
+	if !m.SslCertificateName.IsNull() {
+		for _, area := range api.DomainAreas(areaCode) {
+			certNames[area] = m.SslCertificateName.ValueString()
+		}
+		return certNames
+	}
+	if !m.CertNameCn.IsNull() {
+		certNames[api.DomainAreaCn] = m.CertNameCn.ValueString()
+	}
+	if !m.CertNameAbroad.IsNull() {
+		certNames[api.DomainAreaAbroad] = m.CertNameAbroad.ValueString()
+	}
+	return certNames
+}
+
+// certNamePath returns the path of attribute that sets the certificate name
+// of area.
+func (m *cdnDomainSslAssociationModel) certNamePath(area string) path.Path {
+	if !m.SslCertificateName.IsNull() {
+		return path.Root("ssl_certificate_name")
+	}
+	return path.Root("cert_name_" + area)
 }
 
 type cdnDomainSslAssociationResource struct {
@@ -22,7 +72,11 @@ type cdnDomainSslAssociationResource struct {
 }
 
 var (
-	_ resource.Resource = &cdnDomainSslAssociationResource{}
+	_ resource.Resource                     = &cdnDomainSslAssociationResource{}
+	_ resource.ResourceWithConfigure        = &cdnDomainSslAssociationResource{}
+	_ resource.ResourceWithConfigValidators = &cdnDomainSslAssociationResource{}
+	_ resource.ResourceWithImportState      = &cdnDomainSslAssociationResource{}
+	_ resource.ResourceWithModifyPlan       = &cdnDomainSslAssociationResource{}
 )
 
 func NewCdnDomainSslResource() resource.Resource {
@@ -33,25 +87,61 @@ func (r *cdnDomainSslAssociationResource) Metadata(_ context.Context, req resour
 	resp.TypeName = req.ProviderTypeName + "_cdn_domain_ssl_association"
 }
 
-func (r *cdnDomainSslAssociationResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *cdnDomainSslAssociationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "",
+		Description: "This resource enables HTTPS of acceleration domain with ssl certificates.",
 		Attributes: map[string]schema.Attribute{
+			"project_id": projectIdResourceAttribute(),
 			"domain_id": &schema.StringAttribute{
 				Description: "Id of acceleration domain, generated by ucloud.",
 				Required:    true,
-			},
-			"ssl_certificate_name": &schema.StringAttribute{
-				Description: "Ssl certificate name.",
-				Required:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"ssl_certificate_name": &schema.StringAttribute{
+				Description: "Ssl certificate name used in all areas of the domain. Changing it switches the domain to the new certificate in place. " +
+					"Conflicts with `cert_name_cn` and `cert_name_abroad`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("cert_name_cn"), path.MatchRoot("cert_name_abroad")),
+				},
+			},
+			"cert_name_cn": &schema.StringAttribute{
+				Description: "Ssl certificate name used in China. HTTPS in China is disabled if the value is unset.",
+				Optional:    true,
+			},
+			"cert_name_abroad": &schema.StringAttribute{
+				Description: "Ssl certificate name used outside China. HTTPS outside China is disabled if the value is unset.",
+				Optional:    true,
+			},
+			"skip_san_check": &schema.BoolAttribute{
+				Description: "Skip checking that the subject alternative names of certificate cover the domain. Default is false.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
 
+func (r *cdnDomainSslAssociationResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.AtLeastOneOf(
+			path.MatchRoot("ssl_certificate_name"),
+			path.MatchRoot("cert_name_cn"),
+			path.MatchRoot("cert_name_abroad"),
+		),
+	}
+}
+
 func (r *cdnDomainSslAssociationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -65,9 +155,19 @@ func (r *cdnDomainSslAssociationResource) Create(ctx context.Context, req resour
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := api.UpdateDomainHttpsConfig(r.client, model.DomainId.ValueString(), true, model.SslCertificateName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("[API ERROR] Fail to Create CdnDomainSslAssociation", err.Error())
+	model.ProjectId = types.StringValue(projectIdOf(r.client, model.ProjectId))
+
+	createTimeout, diags := model.Timeouts.Create(ctx, defaultCdnDomainSslAssociationCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(r.updateHttpsConfig(ctx, model.ProjectId.ValueString(), model.DomainId.ValueString(), nil, model)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
 }
@@ -79,31 +179,90 @@ func (r *cdnDomainSslAssociationResource) Read(ctx context.Context, req resource
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	model.ProjectId = types.StringValue(projectIdOf(r.client, model.ProjectId))
 
-	domainConfig, err := api.GetUcdnDomainConfig(r.client, model.DomainId.ValueString())
+	domainConfig, err := api.GetUcdnDomainConfig(ctx, r.client, model.ProjectId.ValueString(), model.DomainId.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR] Fail to Read CdnDomainSslAssociation", err.Error())
 		return
 	}
+	if domainConfig == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	remoteCertNames := make(map[string]string)
+	if api.HttpsEnabled(domainConfig.HttpsStatusCn) {
+		remoteCertNames[api.DomainAreaCn] = domainConfig.CertNameCn
+	}
+	if api.HttpsEnabled(domainConfig.HttpsStatusAbroad) {
+		remoteCertNames[api.DomainAreaAbroad] = domainConfig.CertNameAbroad
+	}
 
-	if domainConfig == nil || domainConfig.HttpsStatusCn == "disable" {
+	// Only the areas managed by this resource are read back, so that another
+	// association can manage the other area of the same domain.
+	enabled := false
+	if !model.SslCertificateName.IsNull() {
+		certName := ""
+		for i, area := range api.DomainAreas(domainConfig.AreaCode) {
+			name, ok := remoteCertNames[area]
+			enabled = enabled || ok
+			if i == 0 {
+				certName = name
+			} else if name != certName {
+				certName = ""
+			}
+		}
+		model.SslCertificateName = types.StringValue(certName)
+	} else {
+		if !model.CertNameCn.IsNull() {
+			name, ok := remoteCertNames[api.DomainAreaCn]
+			enabled = enabled || ok
+			model.CertNameCn = types.StringValue(name)
+			if !ok {
+				model.CertNameCn = types.StringNull()
+			}
+		}
+		if !model.CertNameAbroad.IsNull() {
+			name, ok := remoteCertNames[api.DomainAreaAbroad]
+			enabled = enabled || ok
+			model.CertNameAbroad = types.StringValue(name)
+			if !ok {
+				model.CertNameAbroad = types.StringNull()
+			}
+		}
+	}
+	if !enabled {
 		resp.State.RemoveResource(ctx)
 		return
 	}
 
-	model.SslCertificateName = types.StringPointerValue(&domainConfig.CertNameCn)
-	resp.State.Set(ctx, &model)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
 }
 
 func (r *cdnDomainSslAssociationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var model *cdnDomainSslAssociationModel
+	var model, state *cdnDomainSslAssociationModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := api.UpdateDomainHttpsConfig(r.client, model.DomainId.ValueString(), true, model.SslCertificateName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("[API ERROR] Fail to Update CdnDomainSslAssociation", err.Error())
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model.ProjectId = state.ProjectId
+
+	updateTimeout, diags := model.Timeouts.Update(ctx, defaultCdnDomainSslAssociationUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(r.updateHttpsConfig(ctx, model.ProjectId.ValueString(), model.DomainId.ValueString(), state, model)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
 }
@@ -114,8 +273,194 @@ func (r *cdnDomainSslAssociationResource) Delete(ctx context.Context, req resour
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := api.UpdateDomainHttpsConfig(r.client, model.DomainId.ValueString(), false, model.SslCertificateName.ValueString())
+	model.ProjectId = types.StringValue(projectIdOf(r.client, model.ProjectId))
+
+	deleteTimeout, diags := model.Timeouts.Delete(ctx, defaultCdnDomainSslAssociationDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(r.updateHttpsConfig(ctx, model.ProjectId.ValueString(), model.DomainId.ValueString(), model, nil)...)
+}
+
+func (r *cdnDomainSslAssociationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan *cdnDomainSslAssociationModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Domain or certificate may only be known after apply, in which case
+	// the check is performed during apply.
+	if plan == nil || r.client == nil || plan.SkipSanCheck.ValueBool() || plan.DomainId.IsUnknown() {
+		return
+	}
+	projectId := projectIdOf(r.client, plan.ProjectId)
+	domainConfig, err := api.GetUcdnDomainConfig(ctx, r.client, projectId, plan.DomainId.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("[API ERROR] Fail to Delete CdnDomainSslAssociation", err.Error())
+		resp.Diagnostics.AddAttributeWarning(path.Root("domain_id"), "SAN Check Deferred",
+			fmt.Sprintf("Fail to get domain %s, checking that the certificates cover it is deferred to apply: %s", plan.DomainId.ValueString(), err))
+		return
+	}
+	if domainConfig == nil {
+		return
+	}
+	resp.Diagnostics.Append(r.checkSanCoverage(ctx, projectId, domainConfig, plan)...)
+}
+
+func (r *cdnDomainSslAssociationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	projectId, id := parseImportId(r.client, req.ID)
+	domainConfig, err := getUcdnDomainConfigByIdOrName(ctx, r.client, projectId, id)
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Fail to Get CdnDomain", err.Error())
+		return
+	}
+	if domainConfig == nil {
+		resp.Diagnostics.AddError("Domain Not Found", fmt.Sprintf("Domain %s is not found.", req.ID))
+		return
+	}
+
+	sslCertificateName, certNameCn, certNameAbroad := types.StringNull(), types.StringNull(), types.StringNull()
+	if api.HttpsEnabled(domainConfig.HttpsStatusCn) {
+		certNameCn = types.StringValue(domainConfig.CertNameCn)
+	}
+	if api.HttpsEnabled(domainConfig.HttpsStatusAbroad) {
+		certNameAbroad = types.StringValue(domainConfig.CertNameAbroad)
+	}
+	if certNameCn.IsNull() && certNameAbroad.IsNull() {
+		resp.Diagnostics.AddError("HTTPS Not Enabled", fmt.Sprintf("HTTPS of domain %s is not enabled.", req.ID))
+		return
+	}
+
+	// Certificate used in all areas of the domain is imported as
+	// `ssl_certificate_name`.
+	certName, sameCert := "", true
+	for i, area := range api.DomainAreas(domainConfig.AreaCode) {
+		name := certNameCn
+		if area == api.DomainAreaAbroad {
+			name = certNameAbroad
+		}
+		if name.IsNull() || (i > 0 && name.ValueString() != certName) {
+			sameCert = false
+			break
+		}
+		certName = name.ValueString()
+	}
+	if sameCert {
+		sslCertificateName = types.StringValue(certName)
+		certNameCn, certNameAbroad = types.StringNull(), types.StringNull()
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), projectId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain_id"), domainConfig.DomainId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ssl_certificate_name"), sslCertificateName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cert_name_cn"), certNameCn)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cert_name_abroad"), certNameAbroad)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("skip_san_check"), false)...)
+}
+
+// updateHttpsConfig updates HTTPS config of the areas whose certificate
+// differs between state and plan. A nil state or plan means no area is
+// enabled.
+func (r *cdnDomainSslAssociationResource) updateHttpsConfig(ctx context.Context, projectId, domainId string, state, plan *cdnDomainSslAssociationModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	domainConfig, err := api.GetUcdnDomainConfig(ctx, r.client, projectId, domainId)
+	if err != nil {
+		diags.AddError("[API ERROR] Fail to Get CdnDomain", err.Error())
+		return diags
+	}
+	if domainConfig == nil {
+		if plan != nil {
+			diags.AddAttributeError(path.Root("domain_id"), "Domain Not Found", fmt.Sprintf("Domain %s is not found.", domainId))
+		}
+		return diags
+	}
+
+	oldCertNames, newCertNames := make(map[string]string), make(map[string]string)
+	if state != nil {
+		oldCertNames = state.certNames(domainConfig.AreaCode)
+	}
+	if plan != nil {
+		newCertNames = plan.certNames(domainConfig.AreaCode)
+	}
+
+	if plan != nil && !plan.SkipSanCheck.ValueBool() {
+		diags.Append(r.checkSanCoverage(ctx, projectId, domainConfig, plan)...)
+	}
+
+	domainAreas := api.DomainAreas(domainConfig.AreaCode)
+	for area := range newCertNames {
+		if !contains(domainAreas, area) {
+			diags.AddAttributeError(path.Root("cert_name_"+area), "Invalid Area",
+				fmt.Sprintf("Domain %s is accelerated in area %s, HTTPS can't be enabled in area %s.", domainConfig.Domain, domainConfig.AreaCode, area))
+		}
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	for _, area := range domainAreas {
+		oldCertName, oldOk := oldCertNames[area]
+		newCertName, newOk := newCertNames[area]
+		switch {
+		case newOk && (!oldOk || oldCertName != newCertName):
+			err = api.UpdateDomainHttpsConfig(ctx, r.client, projectId, domainId, area, true, newCertName)
+		case !newOk && oldOk:
+			err = api.UpdateDomainHttpsConfig(ctx, r.client, projectId, domainId, area, false, "")
+		default:
+			continue
+		}
+		if err != nil {
+			diags.AddError("[API ERROR] Fail to Update CdnDomain Https Config", err.Error())
+			return diags
+		}
+	}
+	return diags
+}
+
+// checkSanCoverage checks that the certificates of plan cover the domain.
+// Unknown or not yet created certificates are skipped.
+func (r *cdnDomainSslAssociationResource) checkSanCoverage(ctx context.Context, projectId string, domainConfig *api.DomainConfigInfo, plan *cdnDomainSslAssociationModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, area := range api.DomainAreas(domainConfig.AreaCode) {
+		var certName types.String
+		switch {
+		case !plan.SslCertificateName.IsNull():
+			certName = plan.SslCertificateName
+		case area == api.DomainAreaCn:
+			certName = plan.CertNameCn
+		case area == api.DomainAreaAbroad:
+			certName = plan.CertNameAbroad
+		}
+		if certName.IsNull() || certName.IsUnknown() {
+			continue
+		}
+
+		certList, err := api.GetCertificates(ctx, r.client, projectId, certName.ValueString())
+		if err != nil {
+			diags.AddError("[API ERROR] Fail to Get Certificate", err.Error())
+			return diags
+		}
+		if len(certList) == 0 || certList[0] == nil {
+			continue
+		}
+
+		var names []string
+		if cert, err := parseLeafCertificate(certList[0].UserCert); err == nil {
+			names = append(append([]string{}, cert.DNSNames...), cert.Subject.CommonName)
+		} else {
+			names = append(strings.Split(certList[0].DnsName, ","), certList[0].CommonName)
+		}
+		if !certificateCoversDomain(names, domainConfig.Domain) {
+			diags.AddAttributeError(plan.certNamePath(area), "Certificate Does Not Cover Domain",
+				fmt.Sprintf("Certificate %s with names [%s] does not cover domain %s. Set `skip_san_check` to true to bind it anyway.",
+					certName.ValueString(), strings.Join(names, ", "), domainConfig.Domain))
+		}
 	}
+	return diags
 }
@@ -0,0 +1,289 @@
+package ucloud
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/internal/fakeucdn"
+)
+
+func TestAccCdnDomainSslAssociationResource(t *testing.T) {
+	s := testAccFakeServer(t)
+	cert, key := testAccCertificate(t, "test.example.com")
+
+	config := testAccProviderConfig(s) +
+		testAccSslCertificateResourceConfig("test-cert", cert, key) +
+		testAccCdnDomainResourceConfig("test.example.com", 80) + `
+resource "st-ucloud_cdn_domain_ssl_association" "test" {
+  domain_id            = st-ucloud_cdn_domain.test.domain_id
+  ssl_certificate_name = st-ucloud_ssl_certificate.test.cert_name
+}
+`
+
+	var domainId string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"st-ucloud_cdn_domain_ssl_association.test", "domain_id",
+						"st-ucloud_cdn_domain.test", "domain_id"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain_ssl_association.test", "ssl_certificate_name", "test-cert"),
+					testAccCheckCdnDomainHttpsConfig(s, "st-ucloud_cdn_domain.test", "enable", "test-cert"),
+					func(state *terraform.State) error {
+						domainId = state.RootModule().Resources["st-ucloud_cdn_domain.test"].Primary.Attributes["domain_id"]
+						return nil
+					},
+				),
+			},
+			// HTTPS that is still being enabled is read as enabled.
+			{
+				PreConfig: func() {
+					s.SetHttpsStatus(domainId, "cn", "enableing")
+				},
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				ResourceName:                         "st-ucloud_cdn_domain_ssl_association.test",
+				ImportState:                          true,
+				ImportStateIdFunc:                    testAccCdnDomainImportStateIdFunc("st-ucloud_cdn_domain.test"),
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain_id",
+			},
+			{
+				ResourceName:                         "st-ucloud_cdn_domain_ssl_association.test",
+				ImportState:                          true,
+				ImportStateId:                        "test.example.com",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain_id",
+			},
+			{
+				Config: testAccProviderConfig(s) +
+					testAccSslCertificateResourceConfig("test-cert", cert, key) +
+					testAccCdnDomainResourceConfig("test.example.com", 80),
+				Check: testAccCheckCdnDomainHttpsConfig(s, "st-ucloud_cdn_domain.test", "disable", ""),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainSslAssociationResource_area(t *testing.T) {
+	s := testAccFakeServer(t)
+	cnCert, cnKey := testAccCertificate(t, "test.example.com")
+	abroadCert, abroadKey := testAccCertificate(t, "test.example.com")
+
+	config := func(association string) string {
+		return testAccProviderConfig(s) + fmt.Sprintf(`
+resource "st-ucloud_ssl_certificate" "cn" {
+  cert_name = "cert-cn"
+  cert      = <<-EOT
+%sEOT
+  key       = <<-EOT
+%sEOT
+}
+
+resource "st-ucloud_ssl_certificate" "abroad" {
+  cert_name = "cert-abroad"
+  cert      = <<-EOT
+%sEOT
+  key       = <<-EOT
+%sEOT
+}
+
+resource "st-ucloud_cdn_domain" "test" {
+  domain    = "test.example.com"
+  test_url  = "http://test.example.com/index.html"
+  area_code = "all"
+  cdn_type  = "web"
+
+  origin_conf {
+    origin_ip_list = ["1.1.1.1"]
+  }
+}
+`, cnCert, cnKey, abroadCert, abroadKey) + association
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+resource "st-ucloud_cdn_domain_ssl_association" "test" {
+  domain_id        = st-ucloud_cdn_domain.test.domain_id
+  cert_name_cn     = st-ucloud_ssl_certificate.cn.cert_name
+  cert_name_abroad = st-ucloud_ssl_certificate.abroad.cert_name
+}
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckCdnDomainHttpsConfig(s, "st-ucloud_cdn_domain.test", "enable", "cert-cn"),
+					testAccCheckCdnDomainHttpsConfigAbroad(s, "st-ucloud_cdn_domain.test", "enable", "cert-abroad"),
+				),
+			},
+			{
+				ResourceName:                         "st-ucloud_cdn_domain_ssl_association.test",
+				ImportState:                          true,
+				ImportStateId:                        "test.example.com",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain_id",
+			},
+			{
+				Config: config(`
+resource "st-ucloud_cdn_domain_ssl_association" "test" {
+  domain_id    = st-ucloud_cdn_domain.test.domain_id
+  cert_name_cn = st-ucloud_ssl_certificate.cn.cert_name
+}
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckCdnDomainHttpsConfig(s, "st-ucloud_cdn_domain.test", "enable", "cert-cn"),
+					testAccCheckCdnDomainHttpsConfigAbroad(s, "st-ucloud_cdn_domain.test", "disable", ""),
+				),
+			},
+			{
+				Config: config(`
+resource "st-ucloud_cdn_domain_ssl_association" "test" {
+  domain_id            = st-ucloud_cdn_domain.test.domain_id
+  ssl_certificate_name = st-ucloud_ssl_certificate.abroad.cert_name
+}
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckCdnDomainHttpsConfig(s, "st-ucloud_cdn_domain.test", "enable", "cert-abroad"),
+					testAccCheckCdnDomainHttpsConfigAbroad(s, "st-ucloud_cdn_domain.test", "enable", "cert-abroad"),
+				),
+			},
+			{
+				Config: config(`
+resource "st-ucloud_cdn_domain_ssl_association" "test" {
+  domain_id        = st-ucloud_cdn_domain.test.domain_id
+  cert_name_abroad = st-ucloud_ssl_certificate.abroad.cert_name
+}
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckCdnDomainHttpsConfig(s, "st-ucloud_cdn_domain.test", "disable", ""),
+					testAccCheckCdnDomainHttpsConfigAbroad(s, "st-ucloud_cdn_domain.test", "enable", "cert-abroad"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainSslAssociationResource_invalidArea(t *testing.T) {
+	s := testAccFakeServer(t)
+	cert, key := testAccCertificate(t, "test.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) +
+					testAccSslCertificateResourceConfig("test-cert", cert, key) +
+					testAccCdnDomainResourceConfig("test.example.com", 80) + `
+resource "st-ucloud_cdn_domain_ssl_association" "test" {
+  domain_id        = st-ucloud_cdn_domain.test.domain_id
+  cert_name_abroad = st-ucloud_ssl_certificate.test.cert_name
+}
+`,
+				ExpectError: regexp.MustCompile("Invalid Area"),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainSslAssociationResource_sanCheck(t *testing.T) {
+	s := testAccFakeServer(t)
+	otherCert, otherKey := testAccCertificate(t, "other.example.com")
+
+	config := func(association string) string {
+		return testAccProviderConfig(s) +
+			testAccSslCertificateResourceConfig("test-cert", otherCert, otherKey) +
+			testAccCdnDomainResourceConfig("test.example.com", 80) + association
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+resource "st-ucloud_cdn_domain_ssl_association" "test" {
+  domain_id            = st-ucloud_cdn_domain.test.domain_id
+  ssl_certificate_name = st-ucloud_ssl_certificate.test.cert_name
+}
+`),
+				ExpectError: regexp.MustCompile("Certificate Does Not Cover Domain"),
+			},
+			{
+				Config: config(`
+resource "st-ucloud_cdn_domain_ssl_association" "test" {
+  domain_id            = st-ucloud_cdn_domain.test.domain_id
+  ssl_certificate_name = st-ucloud_ssl_certificate.test.cert_name
+  skip_san_check       = true
+}
+`),
+				Check: testAccCheckCdnDomainHttpsConfig(s, "st-ucloud_cdn_domain.test", "enable", "test-cert"),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainSslAssociationResource_sanCheckWildcard(t *testing.T) {
+	s := testAccFakeServer(t)
+	cert, key := testAccCertificate(t, "example.com", "*.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) +
+					testAccSslCertificateResourceConfig("test-cert", cert, key) +
+					testAccCdnDomainResourceConfig("test.example.com", 80) + `
+resource "st-ucloud_cdn_domain_ssl_association" "test" {
+  domain_id            = st-ucloud_cdn_domain.test.domain_id
+  ssl_certificate_name = st-ucloud_ssl_certificate.test.cert_name
+}
+`,
+				Check: testAccCheckCdnDomainHttpsConfig(s, "st-ucloud_cdn_domain.test", "enable", "test-cert"),
+			},
+		},
+	})
+}
+
+func testAccCheckCdnDomainHttpsConfigAbroad(s *fakeucdn.Server, resourceName, httpsStatus, certName string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+		domain, ok := s.Domain(rs.Primary.Attributes["domain_id"])
+		if !ok {
+			return fmt.Errorf("domain %s not found", rs.Primary.Attributes["domain_id"])
+		}
+		if domain.HttpsStatusAbroad != httpsStatus || domain.CertNameAbroad != certName {
+			return fmt.Errorf("expected abroad https status %s with certificate %q, got %s with %q",
+				httpsStatus, certName, domain.HttpsStatusAbroad, domain.CertNameAbroad)
+		}
+		return nil
+	}
+}
+
+func testAccCheckCdnDomainHttpsConfig(s *fakeucdn.Server, resourceName, httpsStatus, certName string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+		domain, ok := s.Domain(rs.Primary.Attributes["domain_id"])
+		if !ok {
+			return fmt.Errorf("domain %s not found", rs.Primary.Attributes["domain_id"])
+		}
+		if domain.HttpsStatusCn != httpsStatus || domain.CertNameCn != certName {
+			return fmt.Errorf("expected https status %s with certificate %q, got %s with %q",
+				httpsStatus, certName, domain.HttpsStatusCn, domain.CertNameCn)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,928 @@
+package ucloud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/internal/fakeucdn"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+)
+
+func TestAccCdnDomainResource(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCdnDomainDestroy(s),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfig("test.example.com", 80),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("st-ucloud_cdn_domain.test", "domain_id"),
+					resource.TestCheckResourceAttrSet("st-ucloud_cdn_domain.test", "cname"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "origin_conf.origin_host", "test.example.com"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "origin_conf.origin_port", "80"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "cache_conf.cache_rule.#", "1"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "cache_conf.cache_rule.0.path_pattern", "/"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "cache_conf.cache_rule.0.ttl", "60"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "access_control_conf.ip_blacklist.#", "1"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "access_control_conf.refer_conf.refer_type", "blacklist"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "advanced_conf.http_to_https", "true"),
+					testAccCheckCdnDomainOriginPort(s, "st-ucloud_cdn_domain.test", 80),
+				),
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfig("test.example.com", 8080),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "origin_conf.origin_port", "8080"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "status", api.DomainStatusEnable),
+					testAccCheckCdnDomainOriginPort(s, "st-ucloud_cdn_domain.test", 8080),
+				),
+			},
+			{
+				ResourceName:                         "st-ucloud_cdn_domain.test",
+				ImportState:                          true,
+				ImportStateIdFunc:                    testAccCdnDomainImportStateIdFunc("st-ucloud_cdn_domain.test"),
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain_id",
+			},
+			{
+				ResourceName:                         "st-ucloud_cdn_domain.test",
+				ImportState:                          true,
+				ImportStateId:                        "test.example.com",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain_id",
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainResource_backupOrigin(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCdnDomainDestroy(s),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithOrigin("test.example.com", `
+    origin_ip_list = ["1.1.1.1"]
+
+    backup_origin {
+      origin_host = "backup.example.com"
+    }
+`),
+				ExpectError: regexp.MustCompile("Missing Configuration for Required Attribute"),
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithOrigin("test.example.com", `
+    origin_ip_list = ["1.1.1.1"]
+
+    backup_origin {
+      origin_ip_list = ["2.2.2.2", "3.3.3.3"]
+    }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "origin_conf.backup_origin.origin_ip_list.#", "2"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "origin_conf.backup_origin.origin_host", "test.example.com"),
+					testAccCheckCdnDomainRemote(s, "st-ucloud_cdn_domain.test", func(domain *api.DomainConfigInfo) error {
+						if !domain.OriginConf.BackupOriginEnable || len(domain.OriginConf.BackupOriginIpList) != 2 {
+							return fmt.Errorf("unexpected backup origin: %+v", domain.OriginConf)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithOrigin("test.example.com", `
+    origin_ip_list = ["1.1.1.1"]
+
+    backup_origin {
+      origin_ip_list = ["2.2.2.2"]
+      origin_host    = "backup.example.com"
+    }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "origin_conf.backup_origin.origin_ip_list.#", "1"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "origin_conf.backup_origin.origin_host", "backup.example.com"),
+				),
+			},
+			{
+				ResourceName:                         "st-ucloud_cdn_domain.test",
+				ImportState:                          true,
+				ImportStateId:                        "test.example.com",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain_id",
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithOrigin("test.example.com", `
+    origin_ip_list = ["1.1.1.1"]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("st-ucloud_cdn_domain.test", "origin_conf.backup_origin.origin_ip_list.#"),
+					testAccCheckCdnDomainRemote(s, "st-ucloud_cdn_domain.test", func(domain *api.DomainConfigInfo) error {
+						if domain.OriginConf.BackupOriginEnable {
+							return fmt.Errorf("backup origin is still enabled")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainResource_us3Origin(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCdnDomainDestroy(s),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithOrigin("test.example.com", `
+    origin_ip_list = ["bucket.cn-bj.ufileos.com"]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "origin_conf.origin_type", "us3"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "origin_conf.origin_host", "bucket.cn-bj.ufileos.com"),
+					testAccCheckCdnDomainRemote(s, "st-ucloud_cdn_domain.test", func(domain *api.DomainConfigInfo) error {
+						if len(domain.OriginConf.OriginIpList) != 1 || domain.OriginConf.OriginIpList[0] != "bucket.cn-bj.ufileos.com" ||
+							domain.OriginConf.OriginHost != "bucket.cn-bj.ufileos.com" {
+							return fmt.Errorf("unexpected origin config: %+v", domain.OriginConf)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:                         "st-ucloud_cdn_domain.test",
+				ImportState:                          true,
+				ImportStateId:                        "test.example.com",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain_id",
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithOrigin("test.example.com", `
+    origin_ip_list = ["origin.example.org"]
+    origin_host    = "origin.example.org"
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "origin_conf.origin_type", "domain"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainResource_originValidation(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithOrigin("test.example.com", `
+    origin_ip_list = ["1.1.1.1", "origin.example.org"]
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Mixed Origin Addresses"),
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithOrigin("test.example.com", `
+    origin_ip_list = ["not_a_host!"]
+`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Invalid Origin Address"),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainResource_enabled(t *testing.T) {
+	s := testAccFakeServer(t)
+	// Count of actions invoked before each step.
+	actionCount := 0
+	markActions := func() { actionCount = len(s.Actions()) }
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCdnDomainDestroy(s),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigEnabled("test.example.com", false, 80),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "enabled", "false"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "status", api.DomainStatusDisable),
+					testAccCheckCdnDomainStatus(s, "st-ucloud_cdn_domain.test", api.DomainStatusDisable),
+				),
+			},
+			{
+				PreConfig: markActions,
+				Config:    testAccProviderConfig(s) + testAccCdnDomainResourceConfigEnabled("test.example.com", true, 8080),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckCdnDomainUpdateActions(s, &actionCount, "UpdateUcdnDomainConfig", "UpdateUcdnDomainStatus"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "status", api.DomainStatusEnable),
+					testAccCheckCdnDomainStatus(s, "st-ucloud_cdn_domain.test", api.DomainStatusEnable),
+					testAccCheckCdnDomainOriginPort(s, "st-ucloud_cdn_domain.test", 8080),
+				),
+			},
+			{
+				PreConfig: markActions,
+				Config:    testAccProviderConfig(s) + testAccCdnDomainResourceConfigEnabled("test.example.com", false, 80),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckCdnDomainUpdateActions(s, &actionCount, "UpdateUcdnDomainConfig", "UpdateUcdnDomainStatus"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "enabled", "false"),
+					testAccCheckCdnDomainStatus(s, "st-ucloud_cdn_domain.test", api.DomainStatusDisable),
+					testAccCheckCdnDomainOriginPort(s, "st-ucloud_cdn_domain.test", 80),
+				),
+			},
+			{
+				ResourceName:                         "st-ucloud_cdn_domain.test",
+				ImportState:                          true,
+				ImportStateId:                        "test.example.com",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain_id",
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainResource_projectId(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCdnDomainDestroy(s),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithOrigin("test.example.com", `
+    origin_ip_list = ["1.1.1.1"]
+`) + `
+resource "st-ucloud_cdn_domain" "other" {
+  project_id = "org-other"
+  domain     = "other.example.com"
+  test_url   = "http://other.example.com/index.html"
+  area_code  = "cn"
+  cdn_type   = "web"
+
+  origin_conf {
+    origin_ip_list = ["1.1.1.1"]
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "project_id", "org-test"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.other", "project_id", "org-other"),
+					testAccCheckCdnDomainProject(s, "st-ucloud_cdn_domain.test", "org-test"),
+					testAccCheckCdnDomainProject(s, "st-ucloud_cdn_domain.other", "org-other"),
+				),
+			},
+			{
+				ResourceName:                         "st-ucloud_cdn_domain.other",
+				ImportState:                          true,
+				ImportStateId:                        "org-other/other.example.com",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain_id",
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainResource_ipBlacklist(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCdnDomainDestroy(s),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithAccessControl("test.example.com", `
+    ip_blacklist = ["192.168.0.0/16", "10.0.0.256"]
+`),
+				ExpectError: regexp.MustCompile("Invalid IP Address"),
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithAccessControl("test.example.com", `
+    ip_blacklist = ["192.168.0.0/16", "2001:db8::1", "2001:db8:1::/48"]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "access_control_conf.ip_blacklist.#", "3"),
+					testAccCheckCdnDomainRemote(s, "st-ucloud_cdn_domain.test", func(domain *api.DomainConfigInfo) error {
+						if len(domain.AccessControlConf.IpBlackList) != 3 {
+							return fmt.Errorf("unexpected ip blacklist: %v", domain.AccessControlConf.IpBlackList)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithAccessControl("test.example.com", `
+    ip_blacklist = []
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "access_control_conf.ip_blacklist.#", "0"),
+					testAccCheckCdnDomainRemote(s, "st-ucloud_cdn_domain.test", func(domain *api.DomainConfigInfo) error {
+						if len(domain.AccessControlConf.IpBlackList) != 0 {
+							return fmt.Errorf("ip blacklist is not cleared: %v", domain.AccessControlConf.IpBlackList)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainResource_headers(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCdnDomainDestroy(s),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithAdvanced("test.example.com", `
+    client_header = [
+      { name = "X-Test", value = "test" },
+      { name = "Cache-Control", value = "max-age=60, public" },
+    ]
+    origin_header = [
+      { name = "X-Forwarded-Host", value = "test.example.com" },
+    ]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "advanced_conf.client_header.#", "2"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "advanced_conf.http_client_header_list.1", "Cache-Control:max-age=60, public"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "advanced_conf.http_origin_header_list.0", "X-Forwarded-Host:test.example.com"),
+					testAccCheckCdnDomainRemote(s, "st-ucloud_cdn_domain.test", func(domain *api.DomainConfigInfo) error {
+						want := []string{"X-Test:test", "Cache-Control:max-age=60, public"}
+						if fmt.Sprint(domain.AdvancedConf.HttpClientHeader) != fmt.Sprint(want) {
+							return fmt.Errorf("expected client headers %v, got %v", want, domain.AdvancedConf.HttpClientHeader)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:                         "st-ucloud_cdn_domain.test",
+				ImportState:                          true,
+				ImportStateId:                        "test.example.com",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain_id",
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithAdvanced("test.example.com", `
+    client_header = [
+      { name = "X Test", value = "test" },
+    ]
+`),
+				ExpectError: regexp.MustCompile("must be a valid http header"),
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithAdvanced("test.example.com", `
+    http_client_header_list = ["X-Test:test"]
+    client_header = [
+      { name = "X-Test", value = "test" },
+    ]
+`),
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+			{
+				// Headers of the legacy list are passed through as is.
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithAdvanced("test.example.com", `
+    http_client_header_list = ["X-Test:raw", "Not a header"]
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("st-ucloud_cdn_domain.test", "advanced_conf.client_header.#"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "advanced_conf.http_client_header_list.1", "Not a header"),
+					testAccCheckCdnDomainRemote(s, "st-ucloud_cdn_domain.test", func(domain *api.DomainConfigInfo) error {
+						want := []string{"X-Test:raw", "Not a header"}
+						if fmt.Sprint(domain.AdvancedConf.HttpClientHeader) != fmt.Sprint(want) {
+							return fmt.Errorf("expected client headers %v, got %v", want, domain.AdvancedConf.HttpClientHeader)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithAdvanced("test.example.com", ""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("st-ucloud_cdn_domain.test", "advanced_conf.client_header.#"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "advanced_conf.http_client_header_list.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainResource_importHeaders(t *testing.T) {
+	s := testAccFakeServer(t)
+	headerConfig := testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithAdvanced("test.example.com", `
+    client_header = [
+      { name = "X-Test", value = "test" },
+    ]
+    origin_header = [
+      { name = "X-Forwarded-Host", value = "test.example.com" },
+    ]
+`)
+	legacyConfig := testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithAdvanced("test.example.com", `
+    http_client_header_list = ["X-Test:raw", "Not a header"]
+`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCdnDomainDestroy(s),
+		Steps: []resource.TestStep{
+			{
+				Config: headerConfig,
+			},
+			{
+				Config:             headerConfig,
+				ResourceName:       "st-ucloud_cdn_domain.test",
+				ImportState:        true,
+				ImportStateId:      "test.example.com",
+				ImportStatePersist: true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					attrs := states[0].Attributes
+					if attrs["advanced_conf.client_header.#"] != "1" || attrs["advanced_conf.client_header.0.name"] != "X-Test" {
+						return fmt.Errorf("expected client_header to be imported, got %v", attrs)
+					}
+					if attrs["advanced_conf.origin_header.#"] != "1" {
+						return fmt.Errorf("expected origin_header to be imported, got %v", attrs)
+					}
+					return nil
+				},
+			},
+			// The first plan after import is empty.
+			{
+				Config:   headerConfig,
+				PlanOnly: true,
+			},
+			// Headers that can't be parsed are only imported into the legacy
+			// lists.
+			{
+				Config: legacyConfig,
+			},
+			{
+				Config:             legacyConfig,
+				ResourceName:       "st-ucloud_cdn_domain.test",
+				ImportState:        true,
+				ImportStateId:      "test.example.com",
+				ImportStatePersist: true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					attrs := states[0].Attributes
+					if _, ok := attrs["advanced_conf.client_header.#"]; ok {
+						return fmt.Errorf("expected client_header not to be imported")
+					}
+					if attrs["advanced_conf.http_client_header_list.#"] != "2" {
+						return fmt.Errorf("expected 2 headers in http_client_header_list, got %s", attrs["advanced_conf.http_client_header_list.#"])
+					}
+					return nil
+				},
+			},
+			{
+				Config:   legacyConfig,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainResource_cacheKeyRule(t *testing.T) {
+	s := testAccFakeServer(t)
+	cacheConfig := testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithCache("test.example.com", `
+    cache_rule {
+      path_pattern = "/"
+      ttl          = 60
+    }
+    cache_key_rule {
+      path_pattern = "/static/"
+    }
+    cache_key_rule {
+      path_pattern = "/api/"
+      query_string = "$a+$b"
+      ignore       = false
+    }
+`)
+
+	var domainId string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCdnDomainDestroy(s),
+		Steps: []resource.TestStep{
+			{
+				Config: cacheConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					func(state *terraform.State) error {
+						domainId = state.RootModule().Resources["st-ucloud_cdn_domain.test"].Primary.Attributes["domain_id"]
+						return nil
+					},
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "cache_conf.cache_key_rule.#", "2"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "cache_conf.cache_key_rule.0.query_string", "$querystring"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "cache_conf.cache_key_rule.0.ignore", "true"),
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "cache_conf.cache_key_rule.1.ignore", "false"),
+					testAccCheckCdnDomainRemote(s, "st-ucloud_cdn_domain.test", func(domain *api.DomainConfigInfo) error {
+						want := []ucdn.CacheKeyInfo{
+							{PathPattern: "/static/", QueryString: "$querystring", Ignore: true},
+							{PathPattern: "/api/", QueryString: "$a+$b", Ignore: false},
+						}
+						if !reflect.DeepEqual(domain.CacheConf.CacheKeyList, want) {
+							return fmt.Errorf("expected cache key list %+v, got %+v", want, domain.CacheConf.CacheKeyList)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				ResourceName:                         "st-ucloud_cdn_domain.test",
+				ImportState:                          true,
+				ImportStateId:                        "test.example.com",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain_id",
+			},
+			// Cache key rules changed outside of Terraform show up as drift.
+			{
+				PreConfig: func() {
+					if !s.SetCacheKeyList(domainId, nil) {
+						t.Fatalf("domain %s not found", domainId)
+					}
+				},
+				Config:             cacheConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: cacheConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_cdn_domain.test", "cache_conf.cache_key_rule.#", "2"),
+					testAccCheckCdnDomainRemote(s, "st-ucloud_cdn_domain.test", func(domain *api.DomainConfigInfo) error {
+						if len(domain.CacheConf.CacheKeyList) != 2 {
+							return fmt.Errorf("expected 2 cache key rules, got %+v", domain.CacheConf.CacheKeyList)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestUpgradeCdnDomainStateV0(t *testing.T) {
+	ctx := context.Background()
+	var schemaResp fwresource.SchemaResponse
+	NewCdnDomainResource().Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	stateType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	cases := []struct {
+		name      string
+		cacheConf string
+		wantNull  bool
+	}{
+		{
+			name:      "default rule",
+			cacheConf: `{"cache_rule":[{"path_pattern":"/","description":"","ttl":0,"cache_unit":"sec","cache_behavior":true,"follow_origin_rule":false,"use_regex":false}],"http_code_cache_rule":[]}`,
+			wantNull:  true,
+		},
+		{
+			name:      "configured rule",
+			cacheConf: `{"cache_rule":[{"path_pattern":"/","description":"","ttl":60,"cache_unit":"sec","cache_behavior":true,"follow_origin_rule":false,"use_regex":false}],"http_code_cache_rule":[]}`,
+		},
+		{
+			name:      "default rule with http code rule",
+			cacheConf: `{"cache_rule":[{"path_pattern":"/","description":"","ttl":0,"cache_unit":"sec","cache_behavior":true,"follow_origin_rule":false,"use_regex":false}],"http_code_cache_rule":[{"path_pattern":"/*","description":"","ttl":0,"cache_unit":"sec","cache_behavior":false,"follow_origin_rule":false,"http_code":404,"use_regex":false}]}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := fwresource.UpgradeStateRequest{
+				RawState: &tfprotov6.RawState{
+					JSON: []byte(`{"domain_id":"ucdn-test","domain":"test.example.com","cache_conf":` + c.cacheConf + `}`),
+				},
+			}
+			var resp fwresource.UpgradeStateResponse
+			upgradeCdnDomainStateV0(ctx, req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			state, err := resp.DynamicValue.Unmarshal(stateType)
+			if err != nil {
+				t.Fatalf("upgraded state doesn't match schema: %v", err)
+			}
+			var attrs map[string]tftypes.Value
+			if err := state.As(&attrs); err != nil {
+				t.Fatal(err)
+			}
+			if attrs["cache_conf"].IsNull() != c.wantNull {
+				t.Errorf("expected null cache_conf %v, got %s", c.wantNull, attrs["cache_conf"])
+			}
+		})
+	}
+}
+
+func TestAccCdnDomainResource_importNotFound(t *testing.T) {
+	s := testAccFakeServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        testAccProviderConfig(s) + testAccCdnDomainResourceConfig("missing.example.com", 80),
+				ResourceName:  "st-ucloud_cdn_domain.test",
+				ImportState:   true,
+				ImportStateId: "missing.example.com",
+				ExpectError:   regexp.MustCompile("Domain Not Found"),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainResource_auditFail(t *testing.T) {
+	s := testAccFakeServer(t)
+	s.FailAudit("fail.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig(s) + testAccCdnDomainResourceConfig("fail.example.com", 80),
+				ExpectError: regexp.MustCompile("Domain audit failed"),
+			},
+		},
+	})
+}
+
+func TestAccCdnDomainResource_createTimeout(t *testing.T) {
+	s := testAccFakeServer(t)
+	s.SetPendingPolls(1000)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckCdnDomainDestroy(s),
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithCreateTimeout("test.example.com", "2s"),
+				ExpectError: regexp.MustCompile("Fail to Get CdnDomain Status"),
+			},
+			// The domain stuck in audit is tainted, so it is replaced
+			// instead of being created again.
+			{
+				PreConfig: func() { s.SetPendingPolls(1) },
+				Config:    testAccProviderConfig(s) + testAccCdnDomainResourceConfigWithCreateTimeout("test.example.com", "2s"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckCdnDomainStatus(s, "st-ucloud_cdn_domain.test", api.DomainStatusEnable),
+				),
+			},
+		},
+	})
+}
+
+func testAccCdnDomainResourceConfig(domain string, originPort int) string {
+	return fmt.Sprintf(`
+resource "st-ucloud_cdn_domain" "test" {
+  domain    = %[1]q
+  test_url  = "http://%[1]s/index.html"
+  area_code = "cn"
+  cdn_type  = "web"
+
+  origin_conf {
+    origin_ip_list = ["1.1.1.1"]
+    origin_port    = %[2]d
+  }
+
+  cache_conf {
+    cache_rule {
+      path_pattern   = "/"
+      ttl            = 60
+      cache_behavior = true
+    }
+  }
+
+  access_control_conf = {
+    ip_blacklist = ["10.0.0.1"]
+    refer_conf = {
+      refer_type = "blacklist"
+      refer_list = ["example.org"]
+    }
+  }
+
+  advanced_conf = {
+    http_to_https = true
+  }
+}
+`, domain, originPort)
+}
+
+// testAccCdnDomainResourceConfigWithOrigin returns a minimal domain config
+// whose origin_conf block has the given content.
+func testAccCdnDomainResourceConfigWithOrigin(domain, originConf string) string {
+	return fmt.Sprintf(`
+resource "st-ucloud_cdn_domain" "test" {
+  domain    = %[1]q
+  test_url  = "http://%[1]s/index.html"
+  area_code = "cn"
+  cdn_type  = "web"
+
+  origin_conf {%[2]s  }
+}
+`, domain, originConf)
+}
+
+// testAccCdnDomainResourceConfigWithAccessControl returns a minimal domain
+// config whose access_control_conf has the given content.
+func testAccCdnDomainResourceConfigWithAccessControl(domain, accessControlConf string) string {
+	return fmt.Sprintf(`
+resource "st-ucloud_cdn_domain" "test" {
+  domain    = %[1]q
+  test_url  = "http://%[1]s/index.html"
+  area_code = "cn"
+  cdn_type  = "web"
+
+  origin_conf {
+    origin_ip_list = ["1.1.1.1"]
+  }
+
+  access_control_conf = {%[2]s  }
+}
+`, domain, accessControlConf)
+}
+
+// testAccCdnDomainResourceConfigWithAdvanced returns a minimal domain config
+// whose advanced_conf has the given content.
+func testAccCdnDomainResourceConfigWithAdvanced(domain, advancedConf string) string {
+	return fmt.Sprintf(`
+resource "st-ucloud_cdn_domain" "test" {
+  domain    = %[1]q
+  test_url  = "http://%[1]s/index.html"
+  area_code = "cn"
+  cdn_type  = "web"
+
+  origin_conf {
+    origin_ip_list = ["1.1.1.1"]
+  }
+
+  advanced_conf = {%[2]s  }
+}
+`, domain, advancedConf)
+}
+
+// testAccCdnDomainResourceConfigWithCache returns a minimal domain config
+// whose cache_conf has the given content.
+func testAccCdnDomainResourceConfigWithCache(domain, cacheConf string) string {
+	return fmt.Sprintf(`
+resource "st-ucloud_cdn_domain" "test" {
+  domain    = %[1]q
+  test_url  = "http://%[1]s/index.html"
+  area_code = "cn"
+  cdn_type  = "web"
+
+  origin_conf {
+    origin_ip_list = ["1.1.1.1"]
+  }
+
+  cache_conf {%[2]s  }
+}
+`, domain, cacheConf)
+}
+
+// testAccCdnDomainResourceConfigWithCreateTimeout returns a minimal domain
+// config whose create timeout is createTimeout.
+func testAccCdnDomainResourceConfigWithCreateTimeout(domain, createTimeout string) string {
+	return fmt.Sprintf(`
+resource "st-ucloud_cdn_domain" "test" {
+  domain    = %[1]q
+  test_url  = "http://%[1]s/index.html"
+  area_code = "cn"
+  cdn_type  = "web"
+
+  origin_conf {
+    origin_ip_list = ["1.1.1.1"]
+  }
+
+  timeouts {
+    create = %[2]q
+  }
+}
+`, domain, createTimeout)
+}
+
+func testAccCdnDomainResourceConfigEnabled(domain string, enabled bool, originPort int) string {
+	return fmt.Sprintf(`
+resource "st-ucloud_cdn_domain" "test" {
+  domain    = %[1]q
+  test_url  = "http://%[1]s/index.html"
+  area_code = "cn"
+  cdn_type  = "web"
+  enabled   = %[2]t
+
+  origin_conf {
+    origin_ip_list = ["1.1.1.1"]
+    origin_port    = %[3]d
+  }
+}
+`, domain, enabled, originPort)
+}
+
+func testAccCdnDomainImportStateIdFunc(resourceName string) resource.ImportStateIdFunc {
+	return func(state *terraform.State) (string, error) {
+		rs, ok := state.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found", resourceName)
+		}
+		return rs.Primary.Attributes["domain_id"], nil
+	}
+}
+
+func testAccCheckCdnDomainOriginPort(s *fakeucdn.Server, resourceName string, port int) resource.TestCheckFunc {
+	return testAccCheckCdnDomainRemote(s, resourceName, func(domain *api.DomainConfigInfo) error {
+		if domain.OriginConf.OriginPort != port {
+			return fmt.Errorf("expected origin port %d, got %d", port, domain.OriginConf.OriginPort)
+		}
+		return nil
+	})
+}
+
+func testAccCheckCdnDomainStatus(s *fakeucdn.Server, resourceName string, status string) resource.TestCheckFunc {
+	return testAccCheckCdnDomainRemote(s, resourceName, func(domain *api.DomainConfigInfo) error {
+		if domain.Status != status {
+			return fmt.Errorf("expected status %s, got %s", status, domain.Status)
+		}
+		return nil
+	})
+}
+
+// testAccCheckCdnDomainRemote runs check against the config of the domain
+// stored in the fake server.
+func testAccCheckCdnDomainProject(s *fakeucdn.Server, resourceName string, projectId string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+		remote, ok := s.DomainProject(rs.Primary.Attributes["domain_id"])
+		if !ok {
+			return fmt.Errorf("domain %s not found", rs.Primary.Attributes["domain_id"])
+		}
+		if remote != projectId {
+			return fmt.Errorf("expected domain in project %s, got %s", projectId, remote)
+		}
+		return nil
+	}
+}
+
+// testAccCheckCdnDomainUpdateActions checks that the update actions invoked
+// after the first *from actions are want, in order.
+func testAccCheckCdnDomainUpdateActions(s *fakeucdn.Server, from *int, want ...string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		got := make([]string, 0)
+		for _, action := range s.Actions()[*from:] {
+			if strings.HasPrefix(action, "Update") {
+				got = append(got, action)
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("expected update actions %v, got %v", want, got)
+		}
+		return nil
+	}
+}
+
+func testAccCheckCdnDomainRemote(s *fakeucdn.Server, resourceName string, check func(*api.DomainConfigInfo) error) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+		domain, ok := s.Domain(rs.Primary.Attributes["domain_id"])
+		if !ok {
+			return fmt.Errorf("domain %s not found", rs.Primary.Attributes["domain_id"])
+		}
+		return check(&domain)
+	}
+}
+
+func testAccCheckCdnDomainDestroy(s *fakeucdn.Server) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		for _, rs := range state.RootModule().Resources {
+			if rs.Type != "st-ucloud_cdn_domain" {
+				continue
+			}
+			if _, ok := s.Domain(rs.Primary.Attributes["domain_id"]); ok {
+				return fmt.Errorf("domain %s still exists", rs.Primary.Attributes["domain_id"])
+			}
+		}
+		return nil
+	}
+}
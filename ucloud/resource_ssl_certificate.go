@@ -1,11 +1,19 @@
 package ucloud
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
 	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
@@ -13,10 +21,21 @@ import (
 )
 
 type sslCertificateResourceModel struct {
-	CertName types.String `tfsdk:"cert_name"`
-	CaCert   types.String `tfsdk:"ca_cert"`
-	Cert     types.String `tfsdk:"cert"`
-	Key      types.String `tfsdk:"key"`
+	CertName   types.String `tfsdk:"cert_name"`
+	NamePrefix types.String `tfsdk:"name_prefix"`
+	CaCert     types.String `tfsdk:"ca_cert"`
+	Cert       types.String `tfsdk:"cert"`
+	Key        types.String `tfsdk:"key"`
+
+	NotBefore         types.String `tfsdk:"not_before"`
+	NotAfter          types.String `tfsdk:"not_after"`
+	SubjectCommonName types.String `tfsdk:"subject_common_name"`
+	DnsNames          types.List   `tfsdk:"dns_names"`
+	Issuer            types.String `tfsdk:"issuer"`
+	SerialNumber      types.String `tfsdk:"serial_number"`
+	Fingerprint       types.String `tfsdk:"fingerprint"`
+
+	ProjectId types.String `tfsdk:"project_id"`
 }
 
 type sslCertificateResource struct {
@@ -24,9 +43,11 @@ type sslCertificateResource struct {
 }
 
 var (
-	_ resource.Resource                = &sslCertificateResource{}
-	_ resource.ResourceWithConfigure   = &sslCertificateResource{}
-	_ resource.ResourceWithImportState = &sslCertificateResource{}
+	_ resource.Resource                   = &sslCertificateResource{}
+	_ resource.ResourceWithConfigure      = &sslCertificateResource{}
+	_ resource.ResourceWithImportState    = &sslCertificateResource{}
+	_ resource.ResourceWithModifyPlan     = &sslCertificateResource{}
+	_ resource.ResourceWithValidateConfig = &sslCertificateResource{}
 )
 
 func NewSslCertificateResource() resource.Resource {
@@ -39,32 +60,147 @@ func (r *sslCertificateResource) Metadata(_ context.Context, req resource.Metada
 
 func (r *sslCertificateResource) Schema(_ context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "The resource provides a SSL certificate for CDN domain",
+		Description: "The resource provides a SSL certificate for CDN domain. Certificate can't be deleted while any domain still uses it.",
 		Attributes: map[string]schema.Attribute{
+			"project_id": projectIdResourceAttribute(),
 			"cert_name": &schema.StringAttribute{
-				Description: "The name of certificate",
-				Required:    true,
+				Description: "The name of certificate. Conflicts with `name_prefix`.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("name_prefix")),
+				},
+			},
+			"name_prefix": &schema.StringAttribute{
+				Description: "Creates a unique certificate name beginning with the specified prefix, " +
+					"which allows the certificate to be replaced with `create_before_destroy`. Conflicts with `cert_name`.",
+				Optional: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"ca_cert": &schema.StringAttribute{
-				Description: "CA certificate content",
+				Description: "CA certificate content. Intermediate certificates must be ordered from the issuer of `cert` up to the root.",
 				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					pemCertificateValidator{},
+				},
 			},
 			"cert": &schema.StringAttribute{
-				Description: "Certificate content",
-				Required:    true,
+				Description: "Certificate content. Expired certificate is rejected when it is created or changed. " +
+					"Certificate is replaced if it is changed outside of Terraform.",
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					pemCertificateValidator{},
+				},
 			},
 			"key": &schema.StringAttribute{
-				Description: "Private key content",
+				Description: "Private key content. Only RSA and ECDSA keys matching `cert` are accepted.",
 				Required:    true,
 				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					// Key of an imported certificate is unknown, adopt it
+					// instead of replacing the certificate.
+					stringplanmodifier.RequiresReplaceIf(func(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+						resp.RequiresReplace = !req.StateValue.IsNull()
+					}, "Replace certificate if private key is changed.", "Replace certificate if private key is changed."),
+				},
+				Validators: []validator.String{
+					pemPrivateKeyValidator{},
+				},
+			},
+			"not_before": &schema.StringAttribute{
+				Description: "The time when the certificate becomes valid, in RFC3339 format.",
+				Computed:    true,
+			},
+			"not_after": &schema.StringAttribute{
+				Description: "The time when the certificate expires, in RFC3339 format.",
+				Computed:    true,
+			},
+			"subject_common_name": &schema.StringAttribute{
+				Description: "The common name in the subject of certificate.",
+				Computed:    true,
+			},
+			"dns_names": &schema.ListAttribute{
+				Description: "The DNS names in the subject alternative names of certificate.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"issuer": &schema.StringAttribute{
+				Description: "The distinguished name of certificate issuer.",
+				Computed:    true,
+			},
+			"serial_number": &schema.StringAttribute{
+				Description: "The serial number of certificate in decimal.",
+				Computed:    true,
+			},
+			"fingerprint": &schema.StringAttribute{
+				Description: "The hex encoded SHA-256 fingerprint of certificate.",
+				Computed:    true,
 			},
 		},
 	}
 }
 
+func (r *sslCertificateResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config sslCertificateResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Attribute validators report malformed PEM, only validate the
+	// relationships between certificate, key and chain here.
+	if config.Cert.IsNull() || config.Cert.IsUnknown() {
+		return
+	}
+	certs, err := parseCertificates(config.Cert.ValueString())
+	if err != nil {
+		return
+	}
+	leaf := certs[0]
+
+	if !config.Key.IsNull() && !config.Key.IsUnknown() {
+		if key, err := parsePrivateKey(config.Key.ValueString()); err == nil {
+			if err := verifyPrivateKey(leaf, key); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("key"), "Private Key Mismatch", err.Error())
+			}
+		}
+	}
+
+	if config.CaCert.IsUnknown() {
+		return
+	}
+	chain := certs
+	if !config.CaCert.IsNull() {
+		caCerts, err := parseCertificates(config.CaCert.ValueString())
+		if err != nil {
+			return
+		}
+		chain = append(chain, caCerts...)
+	}
+	if idx, err := verifyCertificateChain(chain); err != nil {
+		attrPath := path.Root("ca_cert")
+		if idx < len(certs) {
+			attrPath = path.Root("cert")
+		}
+		resp.Diagnostics.AddAttributeError(attrPath, "Invalid Certificate Chain", err.Error())
+	}
+}
+
 func (r *sslCertificateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -80,7 +216,12 @@ func (r *sslCertificateResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	err := api.AddCertificate(r.client,
+	if model.CertName.IsUnknown() || model.CertName.IsNull() {
+		model.CertName = types.StringValue(uniqueCertName(model.NamePrefix.ValueString()))
+	}
+	model.ProjectId = types.StringValue(projectIdOf(r.client, model.ProjectId))
+
+	err := api.AddCertificate(ctx, r.client, model.ProjectId.ValueString(),
 		model.CertName.ValueString(),
 		model.Cert.ValueString(),
 		model.Key.ValueString(),
@@ -89,6 +230,7 @@ func (r *sslCertificateResource) Create(ctx context.Context, req resource.Create
 		resp.Diagnostics.AddError("[API ERROR] Failed to Add Certificate", err.Error())
 		return
 	}
+	resp.Diagnostics.Append(updateSslCertificateMetadata(model)...)
 	resp.State.Set(ctx, model)
 }
 
@@ -99,64 +241,174 @@ func (r *sslCertificateResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	certlist, err := api.GetCertificates(r.client, state.CertName.ValueString())
+	state.ProjectId = types.StringValue(projectIdOf(r.client, state.ProjectId))
+	certlist, err := api.GetCertificates(ctx, r.client, state.ProjectId.ValueString(), state.CertName.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("[API ERROR]Fail to get ssl_certificate", err.Error())
 		return
 	}
 
-	if len(certlist) == 0 {
+	if len(certlist) == 0 || certlist[0] == nil {
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	remote := certlist[0]
 
+	remoteNotAfter := types.StringNull()
+	switch {
+	case state.Cert.IsNull():
+		// Imported, certificate content is only available from remote.
+		if remote.UserCert == "" {
+			resp.Diagnostics.AddAttributeError(path.Root("cert"), "Missing Certificate Content",
+				fmt.Sprintf("The content of certificate %q is not returned by UCloud, it can't be imported.", state.CertName.ValueString()))
+			return
+		}
+		state.Cert = types.StringValue(remote.UserCert)
+		if remote.CaCert != "" {
+			state.CaCert = types.StringValue(remote.CaCert)
+		}
+	case remote.UserCert != "":
+		// Certificate is replaced outside of Terraform, write back the remote
+		// content so that the difference with `cert` forces a replacement.
+		remoteCert, err := parseLeafCertificate(remote.UserCert)
+		if err != nil {
+			break
+		}
+		cert, err := parseLeafCertificate(state.Cert.ValueString())
+		if err != nil || certificateFingerprint(cert) != certificateFingerprint(remoteCert) {
+			state.Cert = types.StringValue(remote.UserCert)
+		}
+	case remote.EndTime != 0:
+		// Only the expiry is returned, keep the remote one in `not_after`
+		// so that the difference with `cert` forces a replacement.
+		cert, err := parseLeafCertificate(state.Cert.ValueString())
+		if err == nil && cert.NotAfter.Unix() != int64(remote.EndTime) {
+			remoteNotAfter = types.StringValue(time.Unix(int64(remote.EndTime), 0).UTC().Format(time.RFC3339))
+		}
+	}
+
+	resp.Diagnostics.Append(updateSslCertificateMetadata(state)...)
+	if !remoteNotAfter.IsNull() {
+		state.NotAfter = remoteNotAfter
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 func (r *sslCertificateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var state, plan sslCertificateResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	var plan sslCertificateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	// Any change of certificate content forces a replacement, the only
+	// in-place update is adopting the key of an imported certificate, which
+	// can't be read back from remote.
+	resp.Diagnostics.Append(updateSslCertificateMetadata(&plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *sslCertificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model sslCertificateResourceModel
+	diags := req.State.Get(ctx, &model)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if state.CertName.Equal(plan.CertName) {
-		resp.Diagnostics.AddError("[API ERROR] Fail to Update Certificate", "cert_name exists")
+	projectId := projectIdOf(r.client, model.ProjectId)
+	certList, err := api.GetCertificates(ctx, r.client, projectId, model.CertName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("[API ERROR] Failed to Get Certificate", err.Error())
+		return
+	}
+	if len(certList) == 0 || certList[0] == nil {
+		return
+	}
+	if len(certList[0].Domains) > 0 {
+		resp.Diagnostics.AddError("Certificate Is In Use",
+			fmt.Sprintf("Certificate %s is still used by domains %s. Associate these domains with another certificate before deleting it.",
+				model.CertName.ValueString(), strings.Join(certList[0].Domains, ", ")))
 		return
 	}
 
-	err := api.AddCertificate(r.client,
-		plan.CertName.ValueString(),
-		plan.Cert.ValueString(),
-		plan.Key.ValueString(),
-		plan.CaCert.ValueString())
+	err = api.DeleteCertificate(ctx, r.client, projectId, model.CertName.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("[API ERROR] Fail to Create New Certificate", err.Error())
+		resp.Diagnostics.AddError("[API ERROR] Failed to Del Certificate", err.Error())
 		return
 	}
+}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+func (r *sslCertificateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	projectId, certName := parseImportId(r.client, req.ID)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), projectId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cert_name"), certName)...)
 }
 
-func (r *sslCertificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var model sslCertificateResourceModel
-	diags := req.State.Get(ctx, &model)
-	resp.Diagnostics.Append(diags...)
+func (r *sslCertificateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan, state *sslCertificateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err := api.DeleteCertificate(r.client, model.CertName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("[API ERROR] Failed to Del Certificate", err.Error())
+	// Resource is being destroyed or the certificate is only known after apply.
+	if plan == nil || plan.Cert.IsUnknown() {
 		return
 	}
+
+	resp.Diagnostics.Append(updateSslCertificateMetadata(plan)...)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+
+	// Only a certificate to be uploaded is rejected if it is expired, an
+	// uploaded certificate expiring later doesn't block plans.
+	if state == nil || !plan.Cert.Equal(state.Cert) {
+		if leaf, err := parseLeafCertificate(plan.Cert.ValueString()); err == nil && time.Now().After(leaf.NotAfter) {
+			resp.Diagnostics.AddAttributeError(path.Root("cert"), "Expired Certificate",
+				fmt.Sprintf("Certificate %q expired at %s.", leaf.Subject.String(), leaf.NotAfter.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	// Expiry of remote certificate differs from `cert`, it is replaced
+	// outside of Terraform.
+	if state != nil && !state.NotAfter.IsNull() && !plan.NotAfter.Equal(state.NotAfter) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("not_after"))
+	}
 }
 
-func (r *sslCertificateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("cert_name"), req, resp)
+// updateSslCertificateMetadata fills the computed metadata of model with the
+// leaf certificate parsed from `cert`. Metadata is null if `cert` is unset.
+func updateSslCertificateMetadata(model *sslCertificateResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	model.NotBefore = types.StringNull()
+	model.NotAfter = types.StringNull()
+	model.SubjectCommonName = types.StringNull()
+	model.DnsNames = types.ListNull(types.StringType)
+	model.Issuer = types.StringNull()
+	model.SerialNumber = types.StringNull()
+	model.Fingerprint = types.StringNull()
+	if model.Cert.IsNull() || model.Cert.IsUnknown() {
+		return diags
+	}
+
+	cert, err := parseLeafCertificate(model.Cert.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("cert"), "Invalid Certificate", err.Error())
+		return diags
+	}
+
+	dnsNames := make([]attr.Value, 0, len(cert.DNSNames))
+	for _, name := range cert.DNSNames {
+		dnsNames = append(dnsNames, types.StringValue(name))
+	}
+	model.NotBefore = types.StringValue(cert.NotBefore.UTC().Format(time.RFC3339))
+	model.NotAfter = types.StringValue(cert.NotAfter.UTC().Format(time.RFC3339))
+	model.SubjectCommonName = types.StringValue(cert.Subject.CommonName)
+	model.DnsNames = types.ListValueMust(types.StringType, dnsNames)
+	model.Issuer = types.StringValue(cert.Issuer.String())
+	model.SerialNumber = types.StringValue(cert.SerialNumber.String())
+	model.Fingerprint = types.StringValue(certificateFingerprint(cert))
+	return diags
 }
@@ -0,0 +1,371 @@
+package ucloud
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/internal/fakeucdn"
+)
+
+func TestAccSslCertificateResource(t *testing.T) {
+	s := testAccFakeServer(t)
+	cert, key := testAccCertificate(t, "test.example.com", "www.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckSslCertificateDestroy(s, "test-cert"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_ssl_certificate.test", "cert_name", "test-cert"),
+					resource.TestCheckResourceAttr("st-ucloud_ssl_certificate.test", "cert", cert),
+					resource.TestCheckResourceAttr("st-ucloud_ssl_certificate.test", "subject_common_name", "test.example.com"),
+					resource.TestCheckResourceAttr("st-ucloud_ssl_certificate.test", "dns_names.#", "2"),
+					resource.TestCheckResourceAttr("st-ucloud_ssl_certificate.test", "dns_names.1", "www.example.com"),
+					resource.TestCheckResourceAttr("st-ucloud_ssl_certificate.test", "issuer", "CN=test.example.com"),
+					resource.TestMatchResourceAttr("st-ucloud_ssl_certificate.test", "fingerprint", regexp.MustCompile("^[0-9a-f]{64}$")),
+					resource.TestCheckResourceAttrSet("st-ucloud_ssl_certificate.test", "not_before"),
+					resource.TestCheckResourceAttrSet("st-ucloud_ssl_certificate.test", "not_after"),
+					resource.TestCheckResourceAttrSet("st-ucloud_ssl_certificate.test", "serial_number"),
+					testAccCheckSslCertificateExists(s, "test-cert"),
+				),
+			},
+			{
+				ResourceName:                         "st-ucloud_ssl_certificate.test",
+				ImportState:                          true,
+				ImportStateId:                        "test-cert",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "cert_name",
+				ImportStateVerifyIgnore:              []string{"key"},
+			},
+			{
+				Config:             testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key),
+				ResourceName:       "st-ucloud_ssl_certificate.test",
+				ImportState:        true,
+				ImportStateId:      "test-cert",
+				ImportStatePersist: true,
+			},
+			// Key of the imported certificate is adopted in place.
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("st-ucloud_ssl_certificate.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_ssl_certificate.test", "key", key),
+					testAccCheckSslCertificateExists(s, "test-cert"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSslCertificateResource_drift(t *testing.T) {
+	s := testAccFakeServer(t)
+	cert, key := testAccCertificate(t, "test.example.com")
+	otherCert, _ := testAccCertificate(t, "test.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key),
+			},
+			{
+				PreConfig: func() {
+					if !s.ReplaceCertificate("org-test", "test-cert", otherCert, "") {
+						t.Fatal("certificate test-cert not found")
+					}
+				},
+				Config:             testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_ssl_certificate.test", "cert", cert),
+					testAccCheckSslCertificateContent(s, "test-cert", cert),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSslCertificateResource_expiryDrift(t *testing.T) {
+	s := testAccFakeServer(t)
+	cert, key := testAccCertificate(t, "test.example.com")
+	otherCert := newTestCertificate(t, "test.example.com", nil, nil, time.Now().Add(48*time.Hour)).certPem
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key),
+			},
+			{
+				PreConfig: func() {
+					s.HideCertificateContent = true
+					if !s.ReplaceCertificate("org-test", "test-cert", otherCert, "") {
+						t.Fatal("certificate test-cert not found")
+					}
+				},
+				Config:             testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("st-ucloud_ssl_certificate.test", "cert", cert),
+					testAccCheckSslCertificateContent(s, "test-cert", cert),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSslCertificateResource_importWithoutContent(t *testing.T) {
+	s := testAccFakeServer(t)
+	cert, key := testAccCertificate(t, "test.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key),
+			},
+			{
+				PreConfig: func() {
+					s.HideCertificateContent = true
+				},
+				Config:        testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key),
+				ResourceName:  "st-ucloud_ssl_certificate.test",
+				ImportState:   true,
+				ImportStateId: "test-cert",
+				ExpectError:   regexp.MustCompile("Missing Certificate Content"),
+			},
+		},
+	})
+}
+
+func TestAccSslCertificateResource_rotation(t *testing.T) {
+	s := testAccFakeServer(t)
+	cert, key := testAccCertificate(t, "test.example.com")
+	newCert, newKey := testAccCertificate(t, "test.example.com")
+
+	var oldName string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateRotationConfig(cert, key),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("st-ucloud_ssl_certificate.test", "cert_name", regexp.MustCompile("^test-")),
+					resource.TestCheckResourceAttrWith("st-ucloud_ssl_certificate.test", "cert_name", func(value string) error {
+						oldName = value
+						return nil
+					}),
+					resource.TestCheckResourceAttrPair(
+						"st-ucloud_cdn_domain_ssl_association.test", "ssl_certificate_name",
+						"st-ucloud_ssl_certificate.test", "cert_name"),
+				),
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateRotationConfig(newCert, newKey),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("st-ucloud_ssl_certificate.test", "cert_name", func(value string) error {
+						if value == oldName {
+							return fmt.Errorf("certificate %s is not replaced", value)
+						}
+						if _, ok := s.Certificate("org-test", oldName); ok {
+							return fmt.Errorf("old certificate %s still exists", oldName)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttrPair(
+						"st-ucloud_cdn_domain_ssl_association.test", "ssl_certificate_name",
+						"st-ucloud_ssl_certificate.test", "cert_name"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSslCertificateResource_inUse(t *testing.T) {
+	s := testAccFakeServer(t)
+	cert, key := testAccCertificate(t, "test.example.com")
+
+	var domainId string
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckSslCertificateDestroy(s, "test-cert"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert, key),
+			},
+			{
+				PreConfig: func() {
+					domainId = s.CreateDomain("org-test", "test.example.com")
+					s.SetHttps(domainId, "cn", "test-cert")
+				},
+				Config:      testAccProviderConfig(s),
+				ExpectError: regexp.MustCompile("Certificate Is In Use"),
+			},
+			{
+				PreConfig: func() {
+					s.SetHttps(domainId, "cn", "")
+				},
+				Config: testAccProviderConfig(s),
+			},
+		},
+	})
+}
+
+func testAccSslCertificateRotationConfig(cert, key string) string {
+	return fmt.Sprintf(`
+resource "st-ucloud_ssl_certificate" "test" {
+  name_prefix = "test-"
+  cert        = <<-EOT
+%sEOT
+  key         = <<-EOT
+%sEOT
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+`, cert, key) + testAccCdnDomainResourceConfig("test.example.com", 80) + `
+resource "st-ucloud_cdn_domain_ssl_association" "test" {
+  domain_id            = st-ucloud_cdn_domain.test.domain_id
+  ssl_certificate_name = st-ucloud_ssl_certificate.test.cert_name
+}
+`
+}
+
+func TestAccSslCertificateResource_validation(t *testing.T) {
+	s := testAccFakeServer(t)
+	notAfter := time.Now().Add(24 * time.Hour)
+	root := newTestCertificate(t, "Test Root", nil, nil, notAfter)
+	intermediate := newTestCertificate(t, "Test Intermediate", nil, root, notAfter)
+	leaf := newTestCertificate(t, "test.example.com", nil, intermediate, notAfter)
+	other := newTestCertificate(t, "other.example.com", nil, nil, notAfter)
+	expired := newTestCertificate(t, "expired.example.com", nil, nil, time.Now().Add(-time.Hour))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", "invalid\n", leaf.keyPem),
+				ExpectError: regexp.MustCompile("Invalid Certificate"),
+			},
+			{
+				Config:      testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", leaf.certPem, other.keyPem),
+				ExpectError: regexp.MustCompile("Private Key Mismatch"),
+			},
+			{
+				Config:      testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", expired.certPem, expired.keyPem),
+				ExpectError: regexp.MustCompile("Expired Certificate"),
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateResourceConfigWithCaCert(
+					"test-cert", leaf.certPem, leaf.keyPem, root.certPem+intermediate.certPem),
+				ExpectError: regexp.MustCompile("Invalid Certificate Chain"),
+			},
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateResourceConfigWithCaCert(
+					"test-cert", leaf.certPem, leaf.keyPem, intermediate.certPem+root.certPem),
+				Check: resource.TestCheckResourceAttr("st-ucloud_ssl_certificate.test", "issuer", "CN=Test Intermediate"),
+			},
+		},
+	})
+}
+
+func TestAccSslCertificateResource_expiredInState(t *testing.T) {
+	s := testAccFakeServer(t)
+	notAfter := time.Now().Add(5 * time.Second)
+	cert := newTestCertificate(t, "test.example.com", nil, nil, notAfter)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert.certPem, cert.keyPem),
+			},
+			// The certificate expires after it is uploaded, which doesn't
+			// make the unchanged config invalid.
+			{
+				PreConfig: func() {
+					time.Sleep(time.Until(notAfter) + time.Second)
+				},
+				Config:   testAccProviderConfig(s) + testAccSslCertificateResourceConfig("test-cert", cert.certPem, cert.keyPem),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccSslCertificateResourceConfigWithCaCert(name, cert, key, caCert string) string {
+	return fmt.Sprintf(`
+resource "st-ucloud_ssl_certificate" "test" {
+  cert_name = %q
+  cert      = <<-EOT
+%sEOT
+  key       = <<-EOT
+%sEOT
+  ca_cert   = <<-EOT
+%sEOT
+}
+`, name, cert, key, caCert)
+}
+
+func testAccSslCertificateResourceConfig(name, cert, key string) string {
+	return fmt.Sprintf(`
+resource "st-ucloud_ssl_certificate" "test" {
+  cert_name = %q
+  cert      = <<-EOT
+%sEOT
+  key       = <<-EOT
+%sEOT
+}
+`, name, cert, key)
+}
+
+func testAccCheckSslCertificateExists(s *fakeucdn.Server, name string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		if _, ok := s.Certificate("org-test", name); !ok {
+			return fmt.Errorf("certificate %s not found", name)
+		}
+		return nil
+	}
+}
+
+func testAccCheckSslCertificateContent(s *fakeucdn.Server, name, userCert string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		c, ok := s.Certificate("org-test", name)
+		if !ok {
+			return fmt.Errorf("certificate %s not found", name)
+		}
+		if c.UserCert != userCert {
+			return fmt.Errorf("certificate %s has unexpected content", name)
+		}
+		return nil
+	}
+}
+
+func testAccCheckSslCertificateDestroy(s *fakeucdn.Server, name string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		if _, ok := s.Certificate("org-test", name); ok {
+			return fmt.Errorf("certificate %s still exists", name)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,28 @@
+package ucloud
+
+import (
+	"context"
+	"strings"
+
+	"github.com/myklst/terraform-provider-st-ucloud/ucloud/api"
+	"github.com/ucloud/ucloud-sdk-go/services/ucdn"
+)
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// getUcdnDomainConfigByIdOrName gets the config of domain identified by
+// either its domain id or its hostname, which is convenient for import.
+func getUcdnDomainConfigByIdOrName(ctx context.Context, client *ucdn.UCDNClient, projectId, id string) (*api.DomainConfigInfo, error) {
+	// Hostname of domain contains dots, which domain id never does.
+	if strings.Contains(id, ".") {
+		return api.GetUcdnDomainConfigByName(ctx, client, projectId, id)
+	}
+	return api.GetUcdnDomainConfig(ctx, client, projectId, id)
+}
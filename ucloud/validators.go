@@ -0,0 +1,128 @@
+package ucloud
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var (
+	_ validator.String = pemCertificateValidator{}
+	_ validator.String = pemPrivateKeyValidator{}
+	_ validator.String = regexValidator{}
+	_ validator.String = originAddressValidator{}
+	_ validator.String = ipOrCidrValidator{}
+)
+
+// pemCertificateValidator validates that a string contains one or more PEM
+// encoded X.509 certificates.
+type pemCertificateValidator struct{}
+
+func (v pemCertificateValidator) Description(_ context.Context) string {
+	return "value must contain PEM encoded X.509 certificates"
+}
+
+func (v pemCertificateValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v pemCertificateValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := parseCertificates(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Certificate", err.Error())
+	}
+}
+
+// pemPrivateKeyValidator validates that a string contains a PEM encoded RSA
+// or ECDSA private key.
+type pemPrivateKeyValidator struct{}
+
+func (v pemPrivateKeyValidator) Description(_ context.Context) string {
+	return "value must be a PEM encoded RSA or ECDSA private key"
+}
+
+func (v pemPrivateKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v pemPrivateKeyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := parsePrivateKey(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Private Key", err.Error())
+	}
+}
+
+// regexValidator validates that a string is a valid regular expression.
+type regexValidator struct{}
+
+func (v regexValidator) Description(_ context.Context) string {
+	return "value must be a valid regular expression"
+}
+
+func (v regexValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v regexValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := regexp.Compile(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Regex", err.Error())
+	}
+}
+
+// originAddressValidator validates that a string is an IP address or a
+// hostname.
+type originAddressValidator struct{}
+
+func (v originAddressValidator) Description(_ context.Context) string {
+	return "value must be an IP address or a hostname"
+}
+
+func (v originAddressValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v originAddressValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	addr := req.ConfigValue.ValueString()
+	if net.ParseIP(addr) == nil && !isHostname(addr) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Origin Address",
+			fmt.Sprintf("%q is neither an IP address nor a hostname.", addr))
+	}
+}
+
+// ipOrCidrValidator validates that a string is an IPv4/IPv6 address or a CIDR.
+type ipOrCidrValidator struct{}
+
+func (v ipOrCidrValidator) Description(_ context.Context) string {
+	return "value must be an IPv4/IPv6 address or a CIDR"
+}
+
+func (v ipOrCidrValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v ipOrCidrValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	addr := req.ConfigValue.ValueString()
+	if net.ParseIP(addr) != nil {
+		return
+	}
+	if _, _, err := net.ParseCIDR(addr); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid IP Address",
+			fmt.Sprintf("%q is neither an IP address nor a CIDR.", addr))
+	}
+}